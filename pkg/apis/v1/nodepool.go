@@ -274,12 +274,17 @@ type NodePool struct {
 // 3. A field is removed from the hash calculations
 const NodePoolHashVersion = "v3"
 
-func (in *NodePool) Hash() string {
-	return fmt.Sprint(lo.Must(hashstructure.Hash(in.Spec.Template, hashstructure.FormatV2, &hashstructure.HashOptions{
+// NodePoolHashOptions returns the options used to hash the NodePool's template for static drift
+func NodePoolHashOptions() *hashstructure.HashOptions {
+	return &hashstructure.HashOptions{
 		SlicesAsSets:    true,
 		IgnoreZeroValue: true,
 		ZeroNil:         true,
-	})))
+	}
+}
+
+func (in *NodePool) Hash() string {
+	return fmt.Sprint(lo.Must(hashstructure.Hash(in.Spec.Template, hashstructure.FormatV2, NodePoolHashOptions())))
 }
 
 // NodePoolList contains a list of NodePool
@@ -46,6 +46,8 @@ func init() {
 }
 
 var _ cloudprovider.CloudProvider = (*CloudProvider)(nil)
+var _ cloudprovider.PreGarbageCollector = (*CloudProvider)(nil)
+var _ cloudprovider.RegistrationHook = (*CloudProvider)(nil)
 
 type CloudProvider struct {
 	InstanceTypes            []*cloudprovider.InstanceType
@@ -54,13 +56,17 @@ type CloudProvider struct {
 
 	mu sync.RWMutex
 	// CreateCalls contains the arguments for every create call that was made since it was cleared
-	CreateCalls        []*v1.NodeClaim
-	AllowedCreateCalls int
-	NextCreateErr      error
-	NextGetErr         error
-	NextDeleteErr      error
-	DeleteCalls        []*v1.NodeClaim
-	GetCalls           []string
+	CreateCalls              []*v1.NodeClaim
+	AllowedCreateCalls       int
+	NextCreateErr            error
+	NextGetErr               error
+	NextDeleteErr            error
+	DeleteCalls              []*v1.NodeClaim
+	NextPreGarbageCollectErr error
+	PreGarbageCollectCalls   []*v1.NodeClaim
+	NextOnRegisteredErr      error
+	OnRegisteredCalls        []*corev1.Node
+	GetCalls                 []string
 
 	CreatedNodeClaims         map[string]*v1.NodeClaim
 	Drifted                   cloudprovider.DriftReason
@@ -91,6 +97,10 @@ func (c *CloudProvider) Reset() {
 	c.NextDeleteErr = nil
 	c.NextGetErr = nil
 	c.DeleteCalls = []*v1.NodeClaim{}
+	c.NextPreGarbageCollectErr = nil
+	c.PreGarbageCollectCalls = nil
+	c.NextOnRegisteredErr = nil
+	c.OnRegisteredCalls = nil
 	c.GetCalls = nil
 	c.Drifted = ""
 	c.NodeClassGroupVersionKind = []schema.GroupVersionKind{
@@ -287,6 +297,32 @@ func (c *CloudProvider) Delete(_ context.Context, nc *v1.NodeClaim) error {
 	return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("no nodeclaim exists with provider id '%s'", nc.Status.ProviderID))
 }
 
+func (c *CloudProvider) PreGarbageCollect(_ context.Context, nc *v1.NodeClaim) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.NextPreGarbageCollectErr != nil {
+		tempError := c.NextPreGarbageCollectErr
+		c.NextPreGarbageCollectErr = nil
+		return tempError
+	}
+	c.PreGarbageCollectCalls = append(c.PreGarbageCollectCalls, nc)
+	return nil
+}
+
+func (c *CloudProvider) OnRegistered(_ context.Context, _ *v1.NodeClaim, node *corev1.Node) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.NextOnRegisteredErr != nil {
+		tempError := c.NextOnRegisteredErr
+		c.NextOnRegisteredErr = nil
+		return tempError
+	}
+	c.OnRegisteredCalls = append(c.OnRegisteredCalls, node)
+	return nil
+}
+
 func (c *CloudProvider) IsDrifted(context.Context, *v1.NodeClaim) (cloudprovider.DriftReason, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -21,6 +21,7 @@ import (
 
 	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -44,6 +45,8 @@ const (
 
 // decorator implements CloudProvider
 var _ cloudprovider.CloudProvider = (*decorator)(nil)
+var _ cloudprovider.PreGarbageCollector = (*decorator)(nil)
+var _ cloudprovider.RegistrationHook = (*decorator)(nil)
 
 var MethodDuration = opmetrics.NewPrometheusHistogram(
 	crmetrics.Registry,
@@ -153,6 +156,36 @@ func (d *decorator) IsDrifted(ctx context.Context, nodeClaim *v1.NodeClaim) (clo
 	return isDrifted, err
 }
 
+// PreGarbageCollect forwards to the decorated CloudProvider when it implements cloudprovider.PreGarbageCollector
+func (d *decorator) PreGarbageCollect(ctx context.Context, nodeClaim *v1.NodeClaim) error {
+	preGarbageCollector, ok := d.CloudProvider.(cloudprovider.PreGarbageCollector)
+	if !ok {
+		return nil
+	}
+	method := "PreGarbageCollect"
+	defer metrics.Measure(MethodDuration, getLabelsMapForDuration(ctx, d, method))()
+	err := preGarbageCollector.PreGarbageCollect(ctx, nodeClaim)
+	if err != nil {
+		ErrorsTotal.Inc(getLabelsMapForError(ctx, d, method, err))
+	}
+	return err
+}
+
+// OnRegistered forwards to the decorated CloudProvider when it implements cloudprovider.RegistrationHook
+func (d *decorator) OnRegistered(ctx context.Context, nodeClaim *v1.NodeClaim, node *corev1.Node) error {
+	registrationHook, ok := d.CloudProvider.(cloudprovider.RegistrationHook)
+	if !ok {
+		return nil
+	}
+	method := "OnRegistered"
+	defer metrics.Measure(MethodDuration, getLabelsMapForDuration(ctx, d, method))()
+	err := registrationHook.OnRegistered(ctx, nodeClaim, node)
+	if err != nil {
+		ErrorsTotal.Inc(getLabelsMapForError(ctx, d, method, err))
+	}
+	return err
+}
+
 // getLabelsMapForDuration is a convenience func that constructs a map[string]string
 // for a prometheus Label map used to compose a duration metric spec
 func getLabelsMapForDuration(ctx context.Context, d *decorator, method string) map[string]string {
@@ -17,13 +17,16 @@ limitations under the License.
 package metrics_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/metrics"
+	"sigs.k8s.io/karpenter/pkg/test"
 )
 
 var _ = Describe("Cloudprovider", func() {
@@ -50,4 +53,41 @@ var _ = Describe("Cloudprovider", func() {
 			})
 		})
 	})
+	Describe("PreGarbageCollect", func() {
+		It("should forward to a CloudProvider that implements the hook", func() {
+			cloudProvider := fake.NewCloudProvider()
+			cloudProvider.NextPreGarbageCollectErr = unknownErr
+			decorated := metrics.Decorate(cloudProvider)
+			preGarbageCollector, ok := decorated.(cloudprovider.PreGarbageCollector)
+			Expect(ok).To(BeTrue())
+
+			nodeClaim := test.NodeClaim()
+			Expect(preGarbageCollector.PreGarbageCollect(context.Background(), nodeClaim)).To(MatchError(unknownErr))
+			Expect(preGarbageCollector.PreGarbageCollect(context.Background(), nodeClaim)).To(Succeed())
+			Expect(cloudProvider.PreGarbageCollectCalls).To(HaveLen(1))
+		})
+		It("should succeed without calling a CloudProvider that doesn't implement the hook", func() {
+			decorated := metrics.Decorate(struct{ cloudprovider.CloudProvider }{fake.NewCloudProvider()})
+			Expect(decorated.(cloudprovider.PreGarbageCollector).PreGarbageCollect(context.Background(), test.NodeClaim())).To(Succeed())
+		})
+	})
+	Describe("OnRegistered", func() {
+		It("should forward to a CloudProvider that implements the hook", func() {
+			cloudProvider := fake.NewCloudProvider()
+			cloudProvider.NextOnRegisteredErr = unknownErr
+			decorated := metrics.Decorate(cloudProvider)
+			registrationHook, ok := decorated.(cloudprovider.RegistrationHook)
+			Expect(ok).To(BeTrue())
+
+			nodeClaim, node := test.NodeClaimAndNode()
+			Expect(registrationHook.OnRegistered(context.Background(), nodeClaim, node)).To(MatchError(unknownErr))
+			Expect(registrationHook.OnRegistered(context.Background(), nodeClaim, node)).To(Succeed())
+			Expect(cloudProvider.OnRegisteredCalls).To(HaveLen(1))
+		})
+		It("should succeed without calling a CloudProvider that doesn't implement the hook", func() {
+			decorated := metrics.Decorate(struct{ cloudprovider.CloudProvider }{fake.NewCloudProvider()})
+			nodeClaim, node := test.NodeClaimAndNode()
+			Expect(decorated.(cloudprovider.RegistrationHook).OnRegistered(context.Background(), nodeClaim, node)).To(Succeed())
+		})
+	})
 })
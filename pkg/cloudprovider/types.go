@@ -89,6 +89,24 @@ type CloudProvider interface {
 	GetSupportedNodeClasses() []status.Object
 }
 
+// PreGarbageCollector is an optional interface that CloudProviders can implement to be notified before Karpenter
+// garbage collects a NodeClaim
+type PreGarbageCollector interface {
+	// PreGarbageCollect is called by the garbage collection controller before it deletes a NodeClaim whose instance
+	// no longer exists. CloudProviders can use this to clean up any associated resources. Returning an error aborts
+	// the deletion of the NodeClaim for that garbage collection pass.
+	PreGarbageCollect(context.Context, *v1.NodeClaim) error
+}
+
+// RegistrationHook is an optional interface that CloudProviders can implement to be notified when a NodeClaim
+// registers
+type RegistrationHook interface {
+	// OnRegistered is called by the lifecycle controller once a NodeClaim's Node has been synced, before the NodeClaim
+	// is marked as registered. CloudProviders can use this to perform bookkeeping against the launched instance.
+	// Returning an error fails the registration reconcile, which is retried.
+	OnRegistered(context.Context, *v1.NodeClaim, *corev1.Node) error
+}
+
 // InstanceType describes the properties of a potential node (either concrete attributes of an instance of this type
 // or supported options in the case of arrays)
 type InstanceType struct {
@@ -18,8 +18,10 @@ package disruption
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -36,7 +38,9 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/hash"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 	"sigs.k8s.io/karpenter/pkg/utils/result"
 )
@@ -48,8 +52,10 @@ type nodeClaimReconciler interface {
 // Controller is a disruption controller that adds StatusConditions to nodeclaims when they meet certain disruption conditions
 // e.g. When the NodeClaim has become empty, then it is marked as "Empty" in the StatusConditions
 type Controller struct {
+	clock         clock.Clock
 	kubeClient    client.Client
 	cloudProvider cloudprovider.CloudProvider
+	rehashLimiter *rate.Limiter
 
 	drift         *Drift
 	consolidation *Consolidation
@@ -59,8 +65,10 @@ type Controller struct {
 // Disruption mechanisms that don't depend on the nodepool (like expiration), should live elsewhere.
 func NewController(clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider) *Controller {
 	return &Controller{
+		clock:         clk,
 		kubeClient:    kubeClient,
 		cloudProvider: cloudProvider,
+		rehashLimiter: rate.NewLimiter(rate.Limit(options.DeferredNodeClaimRehashRate), options.DeferredNodeClaimRehashRate),
 		drift:         &Drift{cloudProvider: cloudProvider},
 		consolidation: &Consolidation{kubeClient: kubeClient, clock: clk},
 	}
@@ -90,6 +98,23 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (re
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 	var results []reconcile.Result
+	// NodeClaims won't have been re-hashed by the NodePool hash controller if re-hashing was deferred, so we re-hash
+	// them here once the NodePool has been updated to the latest hash version. Updating the NodePool's hash version
+	// enqueues all of its NodeClaims at once, so we rate limit the re-hash patches to spread them out over time.
+	if options.FromContext(ctx).DeferNodeClaimRehash &&
+		nodePool.Annotations[v1.NodePoolHashVersionAnnotationKey] == v1.NodePoolHashVersion &&
+		nodeClaim.Annotations[v1.NodePoolHashVersionAnnotationKey] != v1.NodePoolHashVersion {
+		if c.rehashLimiter.AllowN(c.clock.Now(), 1) {
+			hash.UpdateNodeClaimHash(nodePool, nodeClaim)
+			if err := c.kubeClient.Patch(ctx, nodeClaim, client.MergeFrom(stored)); err != nil {
+				return reconcile.Result{}, client.IgnoreNotFound(err)
+			}
+			hash.RecordNodeClaimRehash(nodePool, nodeClaim)
+			stored = nodeClaim.DeepCopy()
+		} else {
+			results = append(results, reconcile.Result{RequeueAfter: time.Second})
+		}
+	}
 	var errs error
 	reconcilers := []nodeClaimReconciler{
 		c.drift,
@@ -25,11 +25,13 @@ import (
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/disruption"
 	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/hash"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
@@ -506,6 +508,124 @@ var _ = Describe("Drift", func() {
 			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
 			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted)).To(BeNil())
 		})
+		It("shouldn't re-hash the NodeClaim if its karpenter.sh/nodepool-hash-version annotation does not match the NodePool's current hash version when NodeClaim re-hashing isn't deferred", func() {
+			nodePool.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        nodePool.Hash(),
+				v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
+			}
+			nodeClaim.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "test-hash-2",
+				v1.NodePoolHashVersionAnnotationKey: "test-version-2",
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, "test-hash-2"))
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, "test-version-2"))
+		})
+		It("should re-hash the NodeClaim if its karpenter.sh/nodepool-hash-version annotation does not match the NodePool's current hash version when NodeClaim re-hashing is deferred", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{DeferNodeClaimRehash: lo.ToPtr(true)}))
+			DeferCleanup(func() {
+				ctx = options.ToContext(ctx, test.Options())
+			})
+			nodePool.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        nodePool.Hash(),
+				v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
+			}
+			nodeClaim.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "test-hash-2",
+				v1.NodePoolHashVersionAnnotationKey: "test-version-2",
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, nodePool.Hash()))
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted)).To(BeNil())
+		})
+		It("should detect drift on a NodeClaim that is lazily re-hashed when NodeClaim re-hashing is deferred", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{DeferNodeClaimRehash: lo.ToPtr(true)}))
+			DeferCleanup(func() {
+				ctx = options.ToContext(ctx, test.Options())
+			})
+			nodePool.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "test-hash-1",
+				v1.NodePoolHashVersionAnnotationKey: "test-version-1",
+			}
+			nodeClaim.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "test-hash-1",
+				v1.NodePoolHashVersionAnnotationKey: "test-version-1",
+			}
+			hash.NodeClaimRehashTotal.Reset()
+			hash.NodeClaimRehashDriftedTotal.Reset()
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, "test-version-1"))
+
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, nodePool.Hash()))
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted)).To(BeNil())
+			ExpectMetricCounterValue(hash.NodeClaimRehashTotal, 1, map[string]string{"nodepool": nodePool.Name})
+
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			nodePool.Spec.Template.Labels = lo.Assign(nodePool.Spec.Template.Labels, map[string]string{"keyLabelTest": "valueLabelTest"})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted).IsTrue()).To(BeTrue())
+		})
+		It("should rate limit lazily re-hashing NodeClaims when NodeClaim re-hashing is deferred", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{DeferNodeClaimRehash: lo.ToPtr(true)}))
+			DeferCleanup(func() {
+				ctx = options.ToContext(ctx, test.Options())
+			})
+			// Use a fresh controller so that re-hashes from other tests don't consume the rate limit
+			controller := disruption.NewController(fakeClock, env.Client, cp)
+			nodePool.ObjectMeta.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        nodePool.Hash(),
+				v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
+			}
+			ExpectApplied(ctx, env.Client, nodePool)
+			nodeClaims := lo.Times(options.DeferredNodeClaimRehashRate+5, func(_ int) *v1.NodeClaim {
+				nc := test.NodeClaim(v1.NodeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							v1.NodePoolLabelKey: nodePool.Name,
+						},
+						Annotations: map[string]string{
+							v1.NodePoolHashAnnotationKey:        "test-hash-1",
+							v1.NodePoolHashVersionAnnotationKey: "test-version-1",
+						},
+					},
+				})
+				nc.StatusConditions().SetTrue(v1.ConditionTypeLaunched)
+				ExpectApplied(ctx, env.Client, nc)
+				return nc
+			})
+			rehashed := func() int {
+				return lo.CountBy(nodeClaims, func(nc *v1.NodeClaim) bool {
+					return ExpectExists(ctx, env.Client, nc).Annotations[v1.NodePoolHashVersionAnnotationKey] == v1.NodePoolHashVersion
+				})
+			}
+
+			// Only the burst of NodeClaims should be re-hashed, the rest should be requeued to be re-hashed later
+			results := lo.Map(nodeClaims, func(nc *v1.NodeClaim, _ int) reconcile.Result {
+				return ExpectObjectReconciled(ctx, env.Client, controller, nc)
+			})
+			Expect(rehashed()).To(Equal(options.DeferredNodeClaimRehashRate))
+			Expect(lo.CountBy(results, func(r reconcile.Result) bool { return r.RequeueAfter > 0 })).To(Equal(5))
+
+			// Once the rate limit has refilled, the remaining NodeClaims should be re-hashed
+			fakeClock.Step(time.Second)
+			for _, nc := range nodeClaims {
+				ExpectObjectReconciled(ctx, env.Client, controller, nc)
+			}
+			Expect(rehashed()).To(Equal(len(nodeClaims)))
+		})
 		It("should not return drifted if karpenter.sh/nodepool-hash-version annotation is not present on the NodeClaim", func() {
 			nodeClaim.ObjectMeta.Annotations = map[string]string{
 				v1.NodePoolHashAnnotationKey: "test-hash-111111111",
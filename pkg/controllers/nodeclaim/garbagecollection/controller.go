@@ -18,6 +18,8 @@ package garbagecollection
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/awslabs/operatorpkg/singleton"
@@ -38,6 +40,7 @@ import (
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 )
@@ -67,6 +70,8 @@ func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	summary := &reconcileSummary{examined: len(nodeClaims)}
+	defer summary.log(ctx)
 	cloudProviderNodeClaims = lo.Filter(cloudProviderNodeClaims, func(nc *v1.NodeClaim, _ int) bool {
 		return nc.DeletionTimestamp.IsZero()
 	})
@@ -80,8 +85,11 @@ func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
 			n.DeletionTimestamp.IsZero() &&
 			!cloudProviderProviderIDs.Has(n.Status.ProviderID)
 	})
+	summary.gone = len(nodeClaims)
 
 	errs := make([]error, len(nodeClaims))
+	garbageCollected := make([]bool, len(nodeClaims))
+	unknownRemaining := make([]time.Duration, len(nodeClaims))
 	workqueue.ParallelizeUntil(ctx, 20, len(nodeClaims), func(i int) {
 		node, err := nodeclaimutils.NodeForNodeClaim(ctx, c.kubeClient, nodeClaims[i])
 		// Ignore these errors since a registered NodeClaim should only have a NotFound node when
@@ -92,13 +100,55 @@ func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
 		// We do a check on the Ready condition of the node since, even though the CloudProvider says the instance
 		// is not around, we know that the kubelet process is still running if the Node Ready condition is true
 		// Similar logic to: https://github.com/kubernetes/kubernetes/blob/3a75a8c8d9e6a1ebd98d8572132e675d4980f184/staging/src/k8s.io/cloud-provider/controllers/nodelifecycle/node_lifecycle_controller.go#L144
-		if node != nil && nodeutils.GetCondition(node, corev1.NodeReady).Status == corev1.ConditionTrue {
-			return
+		if node != nil {
+			switch ready := nodeutils.GetCondition(node, corev1.NodeReady); ready.Status {
+			case corev1.ConditionTrue:
+				return
+			// An Unknown Ready condition means the Node controller has lost contact with the kubelet, which doesn't
+			// necessarily mean that the kubelet is gone, so we treat it like Ready unless we're configured otherwise
+			case corev1.ConditionUnknown:
+				if !options.FromContext(ctx).GCOnUnknownReady {
+					summary.skippedNodeUnknown.Add(1)
+					return
+				}
+				// Wait for the Node to be unreachable for long enough that this isn't just a brief gap in kubelet heartbeats
+				if remaining := options.FromContext(ctx).GCUnknownReadyDuration - c.clock.Since(ready.LastTransitionTime.Time); remaining > 0 {
+					unknownRemaining[i] = remaining
+					summary.skippedNodeUnknown.Add(1)
+					return
+				}
+			}
+		}
+		garbageCollected[i] = true
+	})
+	nodeClaims = lo.Filter(nodeClaims, func(_ *v1.NodeClaim, i int) bool { return garbageCollected[i] })
+	summary.skippedNodeReady = summary.gone - len(nodeClaims) - int(summary.skippedNodeUnknown.Load())
+	if err := c.notify(ctx, nodeClaims); err != nil {
+		if options.FromContext(ctx).GCWebhookStrict {
+			summary.skippedWebhookFailed = len(nodeClaims)
+			return reconcile.Result{}, multierr.Append(multierr.Combine(errs...), err)
+		}
+		log.FromContext(ctx).Error(err, "failed notifying garbage collection webhook")
+	}
+
+	deleteErrs := make([]error, len(nodeClaims))
+	workqueue.ParallelizeUntil(ctx, 20, len(nodeClaims), func(i int) {
+		// Give the CloudProvider a chance to clean up any resources associated with the NodeClaim. If this fails,
+		// we skip deleting the NodeClaim and retry on the next garbage collection pass
+		if preGarbageCollector, ok := c.cloudProvider.(cloudprovider.PreGarbageCollector); ok {
+			if err := preGarbageCollector.PreGarbageCollect(ctx, nodeClaims[i]); err != nil {
+				deleteErrs[i] = fmt.Errorf("running pre-garbage-collect hook, %w", err)
+				summary.skippedPreGarbageCollectFailed.Add(1)
+				return
+			}
 		}
 		if err := c.kubeClient.Delete(ctx, nodeClaims[i]); err != nil {
-			errs[i] = client.IgnoreNotFound(err)
+			if deleteErrs[i] = client.IgnoreNotFound(err); deleteErrs[i] != nil {
+				summary.deleteFailed.Add(1)
+			}
 			return
 		}
+		summary.deleted.Add(1)
 		log.FromContext(ctx).WithValues(
 			"NodeClaim", klog.KObj(nodeClaims[i]),
 			"Node", klog.KRef("", nodeClaims[i].Status.NodeName),
@@ -110,10 +160,17 @@ func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
 			metrics.CapacityTypeLabel: nodeClaims[i].Labels[v1.CapacityTypeLabelKey],
 		})
 	})
-	if err = multierr.Combine(errs...); err != nil {
+	if err = multierr.Combine(append(errs, deleteErrs...)...); err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{RequeueAfter: time.Minute * 2}, nil
+	// Re-check sooner if a NodeClaim will be eligible for garbage collection before our next pass
+	requeueAfter := time.Minute * 2
+	for _, remaining := range unknownRemaining {
+		if remaining > 0 && remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (c *Controller) Register(_ context.Context, m manager.Manager) error {
@@ -122,3 +179,36 @@ func (c *Controller) Register(_ context.Context, m manager.Manager) error {
 		WatchesRawSource(singleton.Source()).
 		Complete(singleton.AsReconciler(c))
 }
+
+// reconcileSummary tracks the garbage collection decisions made during a single reconcile so that they can be logged together
+type reconcileSummary struct {
+	examined         int
+	gone             int
+	skippedNodeReady int
+	// skippedWebhookFailed is the number of NodeClaims that weren't garbage collected because the strict garbage
+	// collection webhook failed
+	skippedWebhookFailed int
+	// The remaining decisions are made in parallel
+	skippedNodeUnknown             atomic.Int64
+	deleted                        atomic.Int64
+	deleteFailed                   atomic.Int64
+	skippedPreGarbageCollectFailed atomic.Int64
+}
+
+func (s *reconcileSummary) log(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	// Only surface the summary at the default log level when there was something to garbage collect
+	if s.gone == 0 {
+		logger = logger.V(1)
+	}
+	logger.WithValues(
+		"examined", s.examined,
+		"gone", s.gone,
+		"deleted", s.deleted.Load(),
+		"delete-failed", s.deleteFailed.Load(),
+		"skipped-node-ready", s.skippedNodeReady,
+		"skipped-node-unknown", s.skippedNodeUnknown.Load(),
+		"skipped-webhook-failed", s.skippedWebhookFailed,
+		"skipped-pre-garbage-collect-failed", s.skippedPreGarbageCollectFailed.Load(),
+	).Info("summarized garbage collection")
+}
@@ -18,21 +18,31 @@ package garbagecollection_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/go-logr/zapr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	clock "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	nodeclaimgarbagecollection "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/garbagecollection"
 	nodeclaimlifcycle "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
@@ -80,6 +90,7 @@ var _ = Describe("GarbageCollection", func() {
 	var nodePool *v1.NodePool
 
 	BeforeEach(func() {
+		ctx = options.ToContext(ctx, test.Options())
 		nodePool = test.NodePool()
 	})
 	It("should delete the NodeClaim when the Node is there in a NotReady state and the instance is gone", func() {
@@ -109,6 +120,153 @@ var _ = Describe("GarbageCollection", func() {
 		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
 		ExpectNotFound(ctx, env.Client, nodeClaim)
 	})
+	Context("Unknown Ready", func() {
+		var nodeClaim *v1.NodeClaim
+		BeforeEach(func() {
+			nodeClaim = test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+			var node *corev1.Node
+			var err error
+			nodeClaim, node, err = ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Mark the node as Unknown after the launch, as the node lifecycle controller does when it loses contact with the kubelet
+			node = ExpectExists(ctx, env.Client, node)
+			node.Status.Conditions = []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionUnknown,
+					LastHeartbeatTime:  metav1.Now(),
+					LastTransitionTime: metav1.Now(),
+					Reason:             "NodeStatusUnknown",
+				},
+			}
+			ExpectApplied(ctx, env.Client, node)
+
+			// Step forward to move past the cache eventual consistency timeout
+			fakeClock.SetTime(time.Now().Add(time.Second * 20))
+
+			// Delete the nodeClaim from the cloudprovider
+			Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+		})
+		It("shouldn't delete the NodeClaim when the Node is in an Unknown state and the instance is gone", func() {
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+			ExpectExists(ctx, env.Client, nodeClaim)
+		})
+		It("should delete the NodeClaim when the Node is in an Unknown state and the instance is gone when GCOnUnknownReady is enabled", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCOnUnknownReady: lo.ToPtr(true)}))
+
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+		})
+		It("should wait until the Node has been in an Unknown state for GCUnknownReadyDuration before deleting the NodeClaim", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCOnUnknownReady: lo.ToPtr(true), GCUnknownReadyDuration: lo.ToPtr(time.Minute)}))
+
+			// Expect the NodeClaim to be re-checked once the Node has been Unknown for long enough
+			result := ExpectSingletonReconciled(ctx, garbageCollectionController)
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+			ExpectExists(ctx, env.Client, nodeClaim)
+
+			fakeClock.Step(time.Minute)
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+		})
+	})
+	It("should call the CloudProvider PreGarbageCollect hook before deleting the NodeClaim", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+		nodeClaim, node, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		ExpectMakeNodesNotReady(ctx, env.Client, node)
+
+		// Step forward to move past the cache eventual consistency timeout
+		fakeClock.SetTime(time.Now().Add(time.Second * 20))
+
+		// Delete the nodeClaim from the cloudprovider
+		Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(cloudProvider.PreGarbageCollectCalls).To(HaveLen(1))
+		Expect(cloudProvider.PreGarbageCollectCalls[0].Name).To(Equal(nodeClaim.Name))
+		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
+	It("shouldn't delete the NodeClaim when the CloudProvider PreGarbageCollect hook fails", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+		nodeClaim, node, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		ExpectMakeNodesNotReady(ctx, env.Client, node)
+
+		// Step forward to move past the cache eventual consistency timeout
+		fakeClock.SetTime(time.Now().Add(time.Second * 20))
+
+		// Delete the nodeClaim from the cloudprovider
+		Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+
+		// Expect the NodeClaim to not be removed since the hook failed
+		cloudProvider.NextPreGarbageCollectErr = fmt.Errorf("failed to release resources")
+		_ = ExpectSingletonReconcileFailed(ctx, garbageCollectionController)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+
+		// Expect the NodeClaim to be removed on the next pass once the hook succeeds
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
+	It("should delete the NodeClaim when the CloudProvider doesn't implement the PreGarbageCollect hook", func() {
+		// Hide the fake CloudProvider's PreGarbageCollect hook behind the CloudProvider interface
+		controller := nodeclaimgarbagecollection.NewController(fakeClock, env.Client, struct{ cloudprovider.CloudProvider }{cloudProvider})
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+		nodeClaim, node, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		ExpectMakeNodesNotReady(ctx, env.Client, node)
+
+		// Step forward to move past the cache eventual consistency timeout
+		fakeClock.SetTime(time.Now().Add(time.Second * 20))
+
+		// Delete the nodeClaim from the cloudprovider
+		Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+
+		ExpectSingletonReconciled(ctx, controller)
+		Expect(cloudProvider.PreGarbageCollectCalls).To(BeEmpty())
+		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
 	It("shouldn't delete the NodeClaim when the Node is there in a Ready state and the instance is gone", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -220,4 +378,233 @@ var _ = Describe("GarbageCollection", func() {
 		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
 		ExpectExists(ctx, env.Client, nodeClaim)
 	})
+	It("shouldn't act on NodeClaims that are already terminating when the instance is gone", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+				Finalizers: []string{v1.TerminationFinalizer},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		nodeClaim, node, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		ExpectMakeNodesNotReady(ctx, env.Client, node)
+
+		// Step forward to move past the cache eventual consistency timeout
+		fakeClock.SetTime(time.Now().Add(time.Second * 20))
+
+		// Start terminating the NodeClaim through the normal termination flow and delete it from the cloudprovider
+		Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+		Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+		// The NodeClaim should be left to the termination controller
+		Expect(cloudProvider.PreGarbageCollectCalls).To(BeEmpty())
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeFalse())
+		Expect(nodeClaim.Finalizers).To(ContainElement(v1.TerminationFinalizer))
+	})
+	Context("Summary", func() {
+		var logs *observer.ObservedLogs
+		var nodeClaims []*v1.NodeClaim
+		var nodes []*corev1.Node
+		BeforeEach(func() {
+			var core zapcore.Core
+			core, logs = observer.New(zapcore.DebugLevel)
+			logger := log.FromContext(ctx)
+			ctx = log.IntoContext(ctx, zapr.NewLogger(zap.New(core)))
+			DeferCleanup(func() {
+				ctx = log.IntoContext(ctx, logger)
+			})
+
+			nodeClaims, nodes = nil, nil
+			for range 3 {
+				nodeClaim := test.NodeClaim(v1.NodeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							v1.NodePoolLabelKey: nodePool.Name,
+						},
+					},
+				})
+				ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+				nodeClaim, node, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				nodeClaims = append(nodeClaims, nodeClaim)
+				nodes = append(nodes, node)
+			}
+			// Step forward to move past the cache eventual consistency timeout
+			fakeClock.SetTime(time.Now().Add(time.Second * 20))
+		})
+		expectSummary := func(expected map[string]any) {
+			GinkgoHelper()
+			entries := logs.FilterMessage("summarized garbage collection").All()
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].ContextMap()).To(Equal(expected))
+			// The summary should be logged at the default log level whenever NodeClaims were gone
+			Expect(entries[0].Level).To(Equal(lo.Ternary(expected["gone"] == int64(0), zapcore.DebugLevel, zapcore.InfoLevel)))
+		}
+		It("should log a summary of the garbage collection decisions", func() {
+			// The first NodeClaim is gone with a NotReady Node and the second is gone with a Ready Node
+			ExpectMakeNodesNotReady(ctx, env.Client, nodes[0])
+			Expect(cloudProvider.Delete(ctx, nodeClaims[0])).To(Succeed())
+			Expect(cloudProvider.Delete(ctx, nodeClaims[1])).To(Succeed())
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+			expectSummary(map[string]any{
+				"examined":                           int64(3),
+				"gone":                               int64(2),
+				"deleted":                            int64(1),
+				"delete-failed":                      int64(0),
+				"skipped-node-ready":                 int64(1),
+				"skipped-node-unknown":               int64(0),
+				"skipped-webhook-failed":             int64(0),
+				"skipped-pre-garbage-collect-failed": int64(0),
+			})
+		})
+		It("should log the summary at debug when no NodeClaims are gone", func() {
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+			expectSummary(map[string]any{
+				"examined":                           int64(3),
+				"gone":                               int64(0),
+				"deleted":                            int64(0),
+				"delete-failed":                      int64(0),
+				"skipped-node-ready":                 int64(0),
+				"skipped-node-unknown":               int64(0),
+				"skipped-webhook-failed":             int64(0),
+				"skipped-pre-garbage-collect-failed": int64(0),
+			})
+		})
+		It("should separate NodeClaims that were skipped due to an Unknown Node from those skipped due to a Ready Node", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCOnUnknownReady: lo.ToPtr(true), GCUnknownReadyDuration: lo.ToPtr(time.Minute)}))
+			// The first NodeClaim is gone with a Ready Node and the second is gone with a Node that has just gone Unknown
+			node := ExpectExists(ctx, env.Client, nodes[1])
+			node.Status.Conditions = []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionUnknown,
+					LastHeartbeatTime:  metav1.NewTime(fakeClock.Now()),
+					LastTransitionTime: metav1.NewTime(fakeClock.Now()),
+					Reason:             "NodeStatusUnknown",
+				},
+			}
+			ExpectApplied(ctx, env.Client, node)
+			Expect(cloudProvider.Delete(ctx, nodeClaims[0])).To(Succeed())
+			Expect(cloudProvider.Delete(ctx, nodeClaims[1])).To(Succeed())
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+			expectSummary(map[string]any{
+				"examined":                           int64(3),
+				"gone":                               int64(2),
+				"deleted":                            int64(0),
+				"delete-failed":                      int64(0),
+				"skipped-node-ready":                 int64(1),
+				"skipped-node-unknown":               int64(1),
+				"skipped-webhook-failed":             int64(0),
+				"skipped-pre-garbage-collect-failed": int64(0),
+			})
+		})
+		It("should include NodeClaims that were skipped due to a PreGarbageCollect failure in the summary", func() {
+			ExpectMakeNodesNotReady(ctx, env.Client, nodes[0])
+			Expect(cloudProvider.Delete(ctx, nodeClaims[0])).To(Succeed())
+			cloudProvider.NextPreGarbageCollectErr = fmt.Errorf("failed to clean up")
+			_ = ExpectSingletonReconcileFailed(ctx, garbageCollectionController)
+
+			expectSummary(map[string]any{
+				"examined":                           int64(3),
+				"gone":                               int64(1),
+				"deleted":                            int64(0),
+				"delete-failed":                      int64(0),
+				"skipped-node-ready":                 int64(0),
+				"skipped-node-unknown":               int64(0),
+				"skipped-webhook-failed":             int64(0),
+				"skipped-pre-garbage-collect-failed": int64(1),
+			})
+		})
+	})
+	Context("Webhook", func() {
+		var server *httptest.Server
+		var notifications []nodeclaimgarbagecollection.Notification
+		var existedAtNotification []bool
+		var statusCode int
+		var nodeClaim *v1.NodeClaim
+		var node *corev1.Node
+
+		BeforeEach(func() {
+			notifications = nil
+			existedAtNotification = nil
+			statusCode = http.StatusOK
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				notification := nodeclaimgarbagecollection.Notification{}
+				Expect(json.NewDecoder(r.Body).Decode(&notification)).To(Succeed())
+				notifications = append(notifications, notification)
+				stored := &v1.NodeClaim{}
+				Expect(env.Client.Get(ctx, client.ObjectKeyFromObject(nodeClaim), stored)).To(Succeed())
+				existedAtNotification = append(existedAtNotification, stored.DeletionTimestamp.IsZero())
+				w.WriteHeader(statusCode)
+			}))
+
+			nodeClaim = test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			var err error
+			nodeClaim, node, err = ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			ExpectMakeNodesNotReady(ctx, env.Client, node)
+
+			// Step forward to move past the cache eventual consistency timeout
+			fakeClock.SetTime(time.Now().Add(time.Second * 20))
+
+			// Delete the nodeClaim from the cloudprovider
+			Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+		It("should notify the webhook with the providerIDs before deleting NodeClaims", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCWebhookURL: lo.ToPtr(server.URL)}))
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+			Expect(notifications).To(HaveLen(1))
+			Expect(notifications[0].Reason).To(Equal(nodeclaimgarbagecollection.NotificationReasonInstanceNotFound))
+			Expect(notifications[0].ProviderIDs).To(ConsistOf(nodeClaim.Status.ProviderID))
+			Expect(existedAtNotification).To(ConsistOf(true))
+			ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+		})
+		It("should delete NodeClaims when the webhook fails and strict mode is disabled", func() {
+			statusCode = http.StatusInternalServerError
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCWebhookURL: lo.ToPtr(server.URL)}))
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+			Expect(notifications).To(HaveLen(1))
+			ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+		})
+		It("shouldn't delete NodeClaims when the webhook fails and strict mode is enabled", func() {
+			statusCode = http.StatusInternalServerError
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCWebhookURL: lo.ToPtr(server.URL), GCWebhookStrict: lo.ToPtr(true)}))
+			_ = ExpectSingletonReconcileFailed(ctx, garbageCollectionController)
+
+			Expect(notifications).To(HaveLen(1))
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+		})
+		It("shouldn't notify the webhook when there are no NodeClaims to delete", func() {
+			ExpectMakeNodesReady(ctx, env.Client, node)
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCWebhookURL: lo.ToPtr(server.URL)}))
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+
+			Expect(notifications).To(BeEmpty())
+			ExpectExists(ctx, env.Client, nodeClaim)
+		})
+	})
 })
@@ -0,0 +1,73 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/samber/lo"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+)
+
+const NotificationReasonInstanceNotFound = "InstanceNotFound"
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// Notification is the payload POSTed to the garbage collection webhook before NodeClaims are deleted
+type Notification struct {
+	Reason      string   `json:"reason"`
+	ProviderIDs []string `json:"providerIDs"`
+}
+
+// notify sends a summary of the NodeClaims that are about to be garbage collected to the configured webhook.
+// This is a no-op if no webhook is configured or if there are no NodeClaims to garbage collect.
+func (c *Controller) notify(ctx context.Context, nodeClaims []*v1.NodeClaim) error {
+	url := options.FromContext(ctx).GCWebhookURL
+	if url == "" || len(nodeClaims) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(Notification{
+		Reason: NotificationReasonInstanceNotFound,
+		ProviderIDs: lo.Map(nodeClaims, func(nc *v1.NodeClaim, _ int) string {
+			return nc.Status.ProviderID
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling garbage collection notification, %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating garbage collection notification request, %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending garbage collection notification, %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sending garbage collection notification, received status code %d", resp.StatusCode)
+	}
+	return nil
+}
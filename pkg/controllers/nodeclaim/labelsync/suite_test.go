@@ -0,0 +1,128 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labelsync_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/labelsync"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var labelSyncController *labelsync.Controller
+var env *test.Environment
+var cloudProvider *fake.CloudProvider
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LabelSync")
+}
+
+var _ = BeforeSuite(func() {
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...), test.WithFieldIndexers(test.NodeProviderIDFieldIndexer(ctx)))
+	ctx = options.ToContext(ctx, test.Options())
+
+	cloudProvider = fake.NewCloudProvider()
+	labelSyncController = labelsync.NewController(env.Client, cloudProvider)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+	cloudProvider.Reset()
+})
+
+var _ = Describe("LabelSync", func() {
+	It("should sync label changes on a registered NodeClaim to its Node", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"custom-label": "custom-value"},
+			},
+		})
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeRegistered)
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+
+		nodeClaim.Labels = lo.Assign(nodeClaim.Labels, map[string]string{
+			"custom-label":       "updated-value",
+			"other-custom-label": "other-custom-value",
+		})
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, labelSyncController, nodeClaim)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Labels).To(HaveKeyWithValue("custom-label", "updated-value"))
+		Expect(node.Labels).To(HaveKeyWithValue("other-custom-label", "other-custom-value"))
+	})
+	It("should not sync labels to the Node before the NodeClaim is registered", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"custom-label": "custom-value"},
+			},
+		})
+		delete(node.Labels, "custom-label")
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, labelSyncController, nodeClaim)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Labels).ToNot(HaveKey("custom-label"))
+	})
+	It("should ignore NodeClaims which aren't managed by this Karpenter instance", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"custom-label": "custom-value"},
+			},
+			Spec: v1.NodeClaimSpec{
+				NodeClassRef: &v1.NodeClassReference{
+					Group: "karpenter.test.sh",
+					Kind:  "UnmanagedNodeClass",
+					Name:  "default",
+				},
+			},
+		})
+		delete(node.Labels, "custom-label")
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeRegistered)
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, labelSyncController, nodeClaim)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Labels).ToNot(HaveKey("custom-label"))
+	})
+	It("should succeed when the Node doesn't exist", func() {
+		nodeClaim := test.NodeClaim()
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeRegistered)
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, labelSyncController, nodeClaim)
+	})
+})
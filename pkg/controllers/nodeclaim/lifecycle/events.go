@@ -18,11 +18,16 @@ package lifecycle
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/events"
+	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
 )
 
 func InsufficientCapacityErrorEvent(nodeClaim *v1.NodeClaim, err error) events.Event {
@@ -54,3 +59,61 @@ func UnregisteredTaintMissingEvent(nodeClaim *v1.NodeClaim) events.Event {
 		DedupeValues:   []string{string(nodeClaim.UID)},
 	}
 }
+
+func RegisteredEvent(nodeClaim *v1.NodeClaim, node *corev1.Node) events.Event {
+	message := fmt.Sprintf("Registered Node %s with allocatable %s", node.Name, formatResources(node.Status.Allocatable))
+	// Only surface the reserved resources when the Node actually reserves something for system overhead
+	if reserved := lo.OmitBy(nodeutils.Reserved(node), func(_ corev1.ResourceName, q resource.Quantity) bool { return q.IsZero() }); len(reserved) > 0 {
+		message = fmt.Sprintf("%s and reserved %s", message, formatResources(reserved))
+	}
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           corev1.EventTypeNormal,
+		Reason:         events.Registered,
+		Message:        message,
+		DedupeValues:   []string{string(nodeClaim.UID)},
+	}
+}
+
+// formatResources returns the resources as a comma-separated list of names and quantities sorted by name
+func formatResources(resources corev1.ResourceList) string {
+	formatted := lo.MapToSlice(resources, func(name corev1.ResourceName, q resource.Quantity) string {
+		return fmt.Sprintf("%s %s", name, q.String())
+	})
+	sort.Strings(formatted)
+	return strings.Join(formatted, ", ")
+}
+
+func NodePartiallySyncedEvent(nodeClaim *v1.NodeClaim, node *corev1.Node) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           corev1.EventTypeWarning,
+		Reason:         events.NodePartiallySynced,
+		Message:        fmt.Sprintf("Synced finalizer and owner references to Node %s but failed to sync labels, annotations, and taints", node.Name),
+		DedupeValues:   []string{string(nodeClaim.UID)},
+	}
+}
+
+func TaintsMissingEvent(nodeClaim *v1.NodeClaim, node *corev1.Node, taints []corev1.Taint) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           corev1.EventTypeWarning,
+		Reason:         events.TaintsOutOfSync,
+		Message:        fmt.Sprintf("Re-applying taints %s which were removed from Node %s", formatTaints(taints), node.Name),
+		DedupeValues:   []string{string(nodeClaim.UID)},
+	}
+}
+
+func TaintsConflictEvent(nodeClaim *v1.NodeClaim, node *corev1.Node, taints []corev1.Taint) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           corev1.EventTypeWarning,
+		Reason:         events.TaintsOutOfSync,
+		Message:        fmt.Sprintf("Unable to sync taints %s since Node %s has conflicting values for them", formatTaints(taints), node.Name),
+		DedupeValues:   []string{string(nodeClaim.UID)},
+	}
+}
+
+func formatTaints(taints []corev1.Taint) string {
+	return strings.Join(lo.Map(taints, func(t corev1.Taint, _ int) string { return formatTaint(&t) }), ", ")
+}
@@ -24,6 +24,18 @@ import (
 	"sigs.k8s.io/karpenter/pkg/metrics"
 )
 
+const (
+	resultLabel = "result"
+
+	registrationResultRegistered         = "registered"
+	registrationResultNodeNotFound       = "node_not_found"
+	registrationResultMultipleNodes      = "multiple_nodes"
+	registrationResultNotLaunched        = "not_launched"
+	registrationResultSyncFailed         = "sync_failed"
+	registrationResultStartupTaintsExist = "startup_taints_exist"
+	registrationResultHookFailed         = "hook_failed"
+)
+
 var InstanceTerminationDurationSeconds = opmetrics.NewPrometheusHistogram(
 	crmetrics.Registry,
 	prometheus.HistogramOpts{
@@ -46,3 +58,26 @@ var NodeClaimTerminationDurationSeconds = opmetrics.NewPrometheusHistogram(
 		Buckets:   prometheus.ExponentialBuckets(1, 2, 12)}, //The threshold values generated here are 1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024. 2048
 	[]string{metrics.NodePoolLabel},
 )
+
+var NodeClaimRegistrationAttemptsTotal = opmetrics.NewPrometheusCounter(
+	crmetrics.Registry,
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.NodeClaimSubsystem,
+		Name:      "registration_attempts_total",
+		Help:      "Number of NodeClaim registration attempts. Attempts that wait on the Node (node_not_found, not_launched, and startup_taints_exist) are counted once each time a NodeClaim starts waiting for a new reason, while failures are counted on every attempt. Labeled by nodepool and the result of the attempt.",
+	},
+	[]string{metrics.NodePoolLabel, resultLabel},
+)
+
+var NodeClaimMemoryAllocatableRatio = opmetrics.NewPrometheusHistogram(
+	crmetrics.Registry,
+	prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.NodeClaimSubsystem,
+		Name:      "memory_allocatable_ratio",
+		Help:      "Ratio of the memory allocatable reported by the Node to the memory allocatable estimated for the NodeClaim, observed at registration. Labeled by nodepool.",
+		Buckets:   prometheus.LinearBuckets(0.8, 0.02, 21), //The threshold values generated here are 0.80, 0.82, ..., 1.20
+	},
+	[]string{metrics.NodePoolLabel},
+)
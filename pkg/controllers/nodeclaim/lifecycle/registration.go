@@ -19,10 +19,13 @@ package lifecycle
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/samber/lo"
+	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -33,31 +36,41 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/metrics"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 )
 
+// startupTaintsExistReason is the reason for NodeClaim conditions that are waiting on the Node's startup taints to be removed
+const startupTaintsExistReason = "StartupTaintsExist"
+
 type Registration struct {
-	kubeClient client.Client
-	recorder   events.Recorder
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	recorder      events.Recorder
 }
 
 func (r *Registration) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
-	if cond := nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered); !cond.IsUnknown() {
+	cond := nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered)
+	if !cond.IsUnknown() {
 		// Ensure that we always set the status condition to the latest generation
 		nodeClaim.StatusConditions().Set(*cond)
 		return reconcile.Result{}, nil
 	}
+	previousReason := cond.Reason
 	node, err := nodeclaimutils.NodeForNodeClaim(ctx, r.kubeClient, nodeClaim)
 	if err != nil {
 		if nodeclaimutils.IsNodeNotFoundError(err) {
 			nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeRegistered, "NodeNotFound", "Node not registered with cluster")
+			recordRegistrationWait(nodeClaim, previousReason, lo.Ternary(nodeClaim.StatusConditions().Get(v1.ConditionTypeLaunched).IsTrue(), registrationResultNodeNotFound, registrationResultNotLaunched))
 			return reconcile.Result{}, nil
 		}
 		if nodeclaimutils.IsDuplicateNodeError(err) {
 			nodeClaim.StatusConditions().SetFalse(v1.ConditionTypeRegistered, "MultipleNodesFound", "Invariant violated, matched multiple nodes")
+			recordRegistrationAttempt(nodeClaim, registrationResultMultipleNodes)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, fmt.Errorf("getting node for nodeclaim, %w", err)
@@ -73,18 +86,48 @@ func (r *Registration) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (
 	}
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("Node", klog.KObj(node)))
 	if err = r.syncNode(ctx, nodeClaim, node); err != nil {
+		// Conflicts are retried immediately against the latest Node, so we don't count them as an attempt
 		if errors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
 		}
+		// The Node may have been deleted between when we fetched it and when we patched it during fast Node churn
+		if errors.IsNotFound(err) {
+			nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeRegistered, "NodeNotFound", "Node not registered with cluster")
+			recordRegistrationWait(nodeClaim, previousReason, registrationResultNodeNotFound)
+			return reconcile.Result{Requeue: true}, nil
+		}
+		recordRegistrationAttempt(nodeClaim, registrationResultSyncFailed)
 		return reconcile.Result{}, err
 	}
+	// Optionally wait for the Node to finish starting up before considering the NodeClaim registered
+	if options.FromContext(ctx).RegistrationRequiresStartupTaintsCleared {
+		if taint, ok := StartupTaintsRemoved(node, nodeClaim); !ok {
+			nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeRegistered, startupTaintsExistReason, fmt.Sprintf("StartupTaint %q still exists", formatTaint(taint)))
+			recordRegistrationWait(nodeClaim, previousReason, registrationResultStartupTaintsExist)
+			return reconcile.Result{}, nil
+		}
+	}
+	if registrationHook, ok := r.cloudProvider.(cloudprovider.RegistrationHook); ok {
+		if err = registrationHook.OnRegistered(ctx, nodeClaim, node); err != nil {
+			recordRegistrationAttempt(nodeClaim, registrationResultHookFailed)
+			return reconcile.Result{}, fmt.Errorf("running post-registration hook, %w", err)
+		}
+	}
+	recordMemoryAllocatableRatio(nodeClaim, node)
+	if options.FromContext(ctx).EnableAllocatableDiffAnnotation {
+		if diff := allocatableDiff(nodeClaim.Status.Allocatable, node.Status.Allocatable); diff != "" {
+			nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1.AllocatableDiffAnnotationKey: diff})
+		}
+	}
 	log.FromContext(ctx).Info("registered nodeclaim")
+	r.recorder.Publish(RegisteredEvent(nodeClaim, node))
 	nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeRegistered)
 	nodeClaim.Status.NodeName = node.Name
 
 	metrics.NodesCreatedTotal.Inc(map[string]string{
 		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
 	})
+	recordRegistrationAttempt(nodeClaim, registrationResultRegistered)
 	if err := r.updateNodePoolRegistrationHealth(ctx, nodeClaim); client.IgnoreNotFound(err) != nil {
 		if errors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
@@ -118,14 +161,30 @@ func (r *Registration) updateNodePoolRegistrationHealth(ctx context.Context, nod
 
 func (r *Registration) syncNode(ctx context.Context, nodeClaim *v1.NodeClaim, node *corev1.Node) error {
 	stored := node.DeepCopy()
-	controllerutil.AddFinalizer(node, v1.TerminationFinalizer)
-
-	node = nodeclaimutils.UpdateNodeOwnerReferences(nodeClaim, node)
+	node = syncNodeOwnership(nodeClaim, node)
 	node.Labels = lo.Assign(node.Labels, nodeClaim.Labels)
 	node.Annotations = lo.Assign(node.Annotations, nodeClaim.Annotations)
 	// Sync all taints inside NodeClaim into the Node taints
-	node.Spec.Taints = scheduling.Taints(node.Spec.Taints).Merge(nodeClaim.Spec.Taints)
-	node.Spec.Taints = scheduling.Taints(node.Spec.Taints).Merge(nodeClaim.Spec.StartupTaints)
+	_, synced := stored.Labels[v1.NodeRegisteredLabelKey]
+	taints := scheduling.Taints(nodeClaim.Spec.Taints)
+	// Only sync startupTaints on the initial sync since they are expected to be removed once the Node has started up
+	if !synced {
+		taints = taints.Merge(nodeClaim.Spec.StartupTaints)
+	}
+	// A taint that was removed from the Node after the initial sync is re-applied, but we warn about it since
+	// something other than Karpenter is managing the Node's taints. We stop syncing the Node once the NodeClaim is
+	// registered, so this only catches taints that are removed while registration is still in progress, e.g. when
+	// registration is held until startup taints are removed or when the NodeClaim's status update failed after the
+	// initial sync.
+	if synced {
+		if missing := missingTaints(stored, nodeClaim.Spec.Taints); len(missing) > 0 {
+			r.recorder.Publish(TaintsMissingEvent(nodeClaim, node, missing))
+		}
+	}
+	if conflicting := conflictingTaints(stored, taints); len(conflicting) > 0 {
+		r.recorder.Publish(TaintsConflictEvent(nodeClaim, node, conflicting))
+	}
+	node.Spec.Taints = scheduling.Taints(node.Spec.Taints).Merge(taints)
 	// Remove karpenter.sh/unregistered taint
 	node.Spec.Taints = lo.Reject(node.Spec.Taints, func(t corev1.Taint, _ int) bool {
 		return t.MatchTaint(&v1.UnregisteredNoExecuteTaint)
@@ -138,8 +197,102 @@ func (r *Registration) syncNode(ctx context.Context, nodeClaim *v1.NodeClaim, no
 		// can cause races due to the fact that it fully replaces the list on a change
 		// Here, we are updating the taint list
 		if err := r.kubeClient.Patch(ctx, node, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
-			return fmt.Errorf("syncing node, %w", err)
+			if errors.IsConflict(err) || errors.IsNotFound(err) {
+				return fmt.Errorf("syncing node, %w", err)
+			}
+			// The termination finalizer and owner references are what allow us to clean up the Node, so we make sure
+			// that they're still applied when the rest of the Node's metadata can't be synced
+			if ownershipErr := r.patchNodeOwnership(ctx, nodeClaim, stored.DeepCopy()); ownershipErr != nil {
+				return fmt.Errorf("syncing node, %w", multierr.Append(err, ownershipErr))
+			}
+			log.FromContext(ctx).Error(err, "synced finalizer and owner references but failed to sync labels, annotations, and taints")
+			r.recorder.Publish(NodePartiallySyncedEvent(nodeClaim, node))
+			return fmt.Errorf("syncing node labels, annotations, and taints after syncing finalizer and owner references, %w", err)
 		}
 	}
 	return nil
 }
+
+func (r *Registration) patchNodeOwnership(ctx context.Context, nodeClaim *v1.NodeClaim, node *corev1.Node) error {
+	stored := node.DeepCopy()
+	node = syncNodeOwnership(nodeClaim, node)
+	if !equality.Semantic.DeepEqual(stored, node) {
+		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// Here, we are updating the finalizer and owner reference lists
+		if err := r.kubeClient.Patch(ctx, node, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			return fmt.Errorf("syncing node finalizer and owner references, %w", err)
+		}
+	}
+	return nil
+}
+
+// syncNodeOwnership adds the termination finalizer and the NodeClaim's owner reference to the Node
+func syncNodeOwnership(nodeClaim *v1.NodeClaim, node *corev1.Node) *corev1.Node {
+	controllerutil.AddFinalizer(node, v1.TerminationFinalizer)
+	return nodeclaimutils.UpdateNodeOwnerReferences(nodeClaim, node)
+}
+
+// missingTaints returns the taints that don't have a taint with a matching key and effect on the Node
+func missingTaints(node *corev1.Node, taints []corev1.Taint) []corev1.Taint {
+	return lo.Reject(taints, func(t corev1.Taint, _ int) bool {
+		return lo.ContainsBy(node.Spec.Taints, func(nodeTaint corev1.Taint) bool { return t.MatchTaint(&nodeTaint) })
+	})
+}
+
+// conflictingTaints returns the taints that can't be synced to the Node because the Node already has a taint with
+// the same key and effect but a different value
+func conflictingTaints(node *corev1.Node, taints []corev1.Taint) []corev1.Taint {
+	return lo.Filter(taints, func(t corev1.Taint, _ int) bool {
+		return lo.ContainsBy(node.Spec.Taints, func(nodeTaint corev1.Taint) bool {
+			return t.MatchTaint(&nodeTaint) && t.Value != nodeTaint.Value
+		})
+	})
+}
+
+// recordRegistrationWait records a registration attempt that is waiting on the Node. The Node watch re-triggers
+// registration often while we wait, so we only record the attempt when the NodeClaim starts waiting for a new reason.
+func recordRegistrationWait(nodeClaim *v1.NodeClaim, previousReason string, result string) {
+	if nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).Reason != previousReason {
+		recordRegistrationAttempt(nodeClaim, result)
+	}
+}
+
+func recordRegistrationAttempt(nodeClaim *v1.NodeClaim, result string) {
+	NodeClaimRegistrationAttemptsTotal.Inc(map[string]string{
+		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+		resultLabel:           result,
+	})
+}
+
+// recordMemoryAllocatableRatio observes how the memory allocatable reported by the Node compares to the estimate that
+// was used to schedule against the NodeClaim, so that operators can tune their memory reservations
+func recordMemoryAllocatableRatio(nodeClaim *v1.NodeClaim, node *corev1.Node) {
+	estimated, observed := nodeClaim.Status.Allocatable.Memory(), node.Status.Allocatable.Memory()
+	if estimated.IsZero() || observed.IsZero() {
+		return
+	}
+	NodeClaimMemoryAllocatableRatio.Observe(observed.AsApproximateFloat64()/estimated.AsApproximateFloat64(), map[string]string{
+		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+	})
+}
+
+// allocatableDiff returns a human-readable summary of the resources whose estimated allocatable differs from the
+// allocatable observed on the Node, e.g. "memory: est 7600Mi, obs 7200Mi (-5.3%)". Resources are sorted by name and
+// joined with semicolons. An empty string is returned if no resources differ.
+func allocatableDiff(estimated, observed corev1.ResourceList) string {
+	var diffs []string
+	for _, name := range lo.Keys(estimated) {
+		est, obs := estimated[name], observed[name]
+		if _, ok := observed[name]; !ok || est.Cmp(obs) == 0 {
+			continue
+		}
+		diff := fmt.Sprintf("%s: est %s, obs %s", name, est.String(), obs.String())
+		if !est.IsZero() {
+			diff += fmt.Sprintf(" (%+.1f%%)", (obs.AsApproximateFloat64()-est.AsApproximateFloat64())/est.AsApproximateFloat64()*100)
+		}
+		diffs = append(diffs, diff)
+	}
+	sort.Strings(diffs)
+	return strings.Join(diffs, "; ")
+}
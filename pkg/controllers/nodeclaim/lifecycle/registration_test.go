@@ -17,17 +17,28 @@ limitations under the License.
 package lifecycle_test
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/awslabs/operatorpkg/object"
 	"github.com/awslabs/operatorpkg/status"
 	operatorpkg "github.com/awslabs/operatorpkg/test/expectations"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	nodeclaimlifecycle "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
 	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 )
@@ -35,6 +46,7 @@ import (
 var _ = Describe("Registration", func() {
 	var nodePool *v1.NodePool
 	BeforeEach(func() {
+		recorder.Reset() // Reset the events that we captured during the run
 		nodePool = test.NodePool()
 	})
 	DescribeTable(
@@ -150,6 +162,69 @@ var _ = Describe("Registration", func() {
 		Expect(recorder.Calls(events.UnregisteredTaintMissing)).To(Equal(1))
 	})
 
+	It("should publish an event with the observed allocatable when the Node comes online", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{
+			ProviderID: nodeClaim.Status.ProviderID,
+			Taints:     []corev1.Taint{v1.UnregisteredNoExecuteTaint},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("7200Mi"),
+				corev1.ResourceCPU:    resource.MustParse("1930m"),
+			},
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(recorder.Calls(events.Registered)).To(Equal(1))
+		Expect(recorder.DetectedEvent(fmt.Sprintf("Registered Node %s with allocatable cpu 1930m, memory 7200Mi", node.Name))).To(BeTrue())
+
+		// We shouldn't publish the event again once the NodeClaim is registered
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		Expect(recorder.Calls(events.Registered)).To(Equal(1))
+	})
+	It("should include the Node's reserved resources in the Registered event", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{
+			ProviderID: nodeClaim.Status.ProviderID,
+			Taints:     []corev1.Taint{v1.UnregisteredNoExecuteTaint},
+			Capacity: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("7200Mi"),
+				corev1.ResourceCPU:    resource.MustParse("1930m"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(recorder.Calls(events.Registered)).To(Equal(1))
+		Expect(recorder.DetectedEvent(fmt.Sprintf("Registered Node %s with allocatable cpu 1930m, memory 7200Mi, pods 110 and reserved cpu 70m, memory 992Mi", node.Name))).To(BeTrue())
+	})
+
 	It("should sync the labels to the Node when the Node comes online", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -260,6 +335,136 @@ var _ = Describe("Registration", func() {
 			},
 		))
 	})
+	It("should warn that taints are out of sync when the Node has a conflicting taint value", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+			Spec: v1.NodeClaimSpec{
+				Taints: []corev1.Taint{
+					{
+						Key:    "custom-taint",
+						Effect: corev1.TaintEffectNoSchedule,
+						Value:  "custom-value",
+					},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{
+			v1.UnregisteredNoExecuteTaint,
+			{
+				Key:    "custom-taint",
+				Effect: corev1.TaintEffectNoSchedule,
+				Value:  "other-value",
+			},
+		}})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		node = ExpectExists(ctx, env.Client, node)
+
+		// Expect the Node's taint to be left alone
+		Expect(node.Spec.Taints).To(ContainElement(corev1.Taint{
+			Key:    "custom-taint",
+			Effect: corev1.TaintEffectNoSchedule,
+			Value:  "other-value",
+		}))
+		Expect(node.Spec.Taints).ToNot(ContainElement(nodeClaim.Spec.Taints[0]))
+		Expect(recorder.Calls(events.TaintsOutOfSync)).To(Equal(1))
+		Expect(recorder.DetectedEvent(fmt.Sprintf("Unable to sync taints custom-taint=custom-value:NoSchedule since Node %s has conflicting values for them", node.Name))).To(BeTrue())
+	})
+	It("should warn that taints are out of sync and re-apply them when they're removed from the Node after the initial sync", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{RegistrationRequiresStartupTaintsCleared: lo.ToPtr(true)}))
+		DeferCleanup(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		taint := corev1.Taint{
+			Key:    "custom-taint",
+			Effect: corev1.TaintEffectNoSchedule,
+			Value:  "custom-value",
+		}
+		startupTaint := corev1.Taint{
+			Key:    "custom-startup-taint",
+			Effect: corev1.TaintEffectNoSchedule,
+		}
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+			Spec: v1.NodeClaimSpec{
+				Taints:        []corev1.Taint{taint},
+				StartupTaints: []corev1.Taint{startupTaint},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		// The NodeClaim won't register until the startup taint is removed, so registration will re-sync the Node
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Spec.Taints).To(ContainElements(taint, startupTaint))
+		Expect(recorder.Calls(events.TaintsOutOfSync)).To(Equal(0))
+
+		// Remove the NodeClaim's taint from the Node
+		node.Spec.Taints = lo.Reject(node.Spec.Taints, func(t corev1.Taint, _ int) bool { return t.MatchTaint(&taint) })
+		ExpectApplied(ctx, env.Client, node)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		node = ExpectExists(ctx, env.Client, node)
+
+		Expect(node.Spec.Taints).To(ContainElements(taint, startupTaint))
+		Expect(recorder.Calls(events.TaintsOutOfSync)).To(Equal(1))
+		Expect(recorder.DetectedEvent(fmt.Sprintf("Re-applying taints custom-taint=custom-value:NoSchedule which were removed from Node %s", node.Name))).To(BeTrue())
+	})
+	It("should warn that taints are out of sync and re-apply them when they're removed from a Node that was already synced", func() {
+		taint := corev1.Taint{
+			Key:    "custom-taint",
+			Effect: corev1.TaintEffectNoSchedule,
+			Value:  "custom-value",
+		}
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+			Spec: v1.NodeClaimSpec{
+				Taints: []corev1.Taint{taint},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		// The Node was synced before, but the NodeClaim wasn't marked as registered, and the taint has since been removed
+		node := test.Node(test.NodeOptions{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodeRegisteredLabelKey: "true",
+				},
+			},
+			ProviderID: nodeClaim.Status.ProviderID,
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		node = ExpectExists(ctx, env.Client, node)
+
+		Expect(node.Spec.Taints).To(ContainElement(taint))
+		Expect(recorder.Calls(events.TaintsOutOfSync)).To(Equal(1))
+		Expect(recorder.DetectedEvent(fmt.Sprintf("Re-applying taints custom-taint=custom-value:NoSchedule which were removed from Node %s", node.Name))).To(BeTrue())
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+	})
 	It("should sync the startupTaints to the Node when the Node comes online", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -428,4 +633,645 @@ var _ = Describe("Registration", func() {
 		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
 		Expect(nodeClaim.Status.NodeName).To(Equal(node.Name))
 	})
+	It("should call the CloudProvider OnRegistered hook with the Node once it has been synced", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(cloudProvider.OnRegisteredCalls).To(BeEmpty())
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(cloudProvider.OnRegisteredCalls).To(HaveLen(1))
+		Expect(cloudProvider.OnRegisteredCalls[0].Name).To(Equal(node.Name))
+		Expect(cloudProvider.OnRegisteredCalls[0].Labels).To(HaveKeyWithValue(v1.NodeRegisteredLabelKey, "true"))
+		Expect(cloudProvider.OnRegisteredCalls[0].Finalizers).To(ContainElement(v1.TerminationFinalizer))
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+	})
+	It("should not mark the NodeClaim as registered if the CloudProvider OnRegistered hook fails", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		cloudProvider.NextOnRegisteredErr = fmt.Errorf("failed to register")
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsUnknown()).To(BeTrue())
+		Expect(nodeClaim.Status.NodeName).To(BeEmpty())
+
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+		Expect(cloudProvider.OnRegisteredCalls).To(HaveLen(1))
+	})
+	It("should register the NodeClaim when the CloudProvider doesn't implement the OnRegistered hook", func() {
+		// Hide the fake CloudProvider's OnRegistered hook behind the CloudProvider interface
+		controller := nodeclaimlifecycle.NewController(fakeClock, env.Client, struct{ cloudprovider.CloudProvider }{cloudProvider}, recorder)
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClaim)
+
+		Expect(cloudProvider.OnRegisteredCalls).To(BeEmpty())
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+		Expect(nodeClaim.Status.NodeName).To(Equal(node.Name))
+	})
+	It("should requeue without erroring if the Node is deleted before it can be synced", func() {
+		// Delete the Node right before the registration controller patches it to simulate fast Node churn
+		kubeClient := &deleteNodeBeforePatchClient{Client: env.Client}
+		controller := nodeclaimlifecycle.NewController(fakeClock, kubeClient, cloudProvider, recorder)
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		result := ExpectObjectReconciled(ctx, env.Client, controller, nodeClaim)
+		Expect(result.Requeue).To(BeTrue())
+
+		ExpectNotFound(ctx, env.Client, node)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsUnknown()).To(BeTrue())
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).Reason).To(Equal("NodeNotFound"))
+		Expect(nodeClaim.Status.NodeName).To(BeEmpty())
+	})
+	It("should still sync the finalizer and owner references when the rest of the Node can't be synced", func() {
+		// Reject any Node patch that updates labels to simulate a rejected label sync
+		kubeClient := &rejectNodeLabelsPatchClient{Client: env.Client}
+		controller := nodeclaimlifecycle.NewController(fakeClock, kubeClient, cloudProvider, recorder)
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		err := ExpectObjectReconcileFailed(ctx, env.Client, controller, nodeClaim)
+		Expect(err.Error()).To(ContainSubstring("after syncing finalizer and owner references"))
+		Expect(recorder.Calls(events.NodePartiallySynced)).To(Equal(1))
+		Expect(recorder.DetectedEvent(fmt.Sprintf("Synced finalizer and owner references to Node %s but failed to sync labels, annotations, and taints", node.Name))).To(BeTrue())
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Finalizers).To(ContainElement(v1.TerminationFinalizer))
+		Expect(node.OwnerReferences).To(ContainElement(metav1.OwnerReference{
+			APIVersion:         object.GVK(nodeClaim).GroupVersion().String(),
+			Kind:               object.GVK(nodeClaim).Kind,
+			Name:               nodeClaim.Name,
+			UID:                nodeClaim.UID,
+			BlockOwnerDeletion: lo.ToPtr(true),
+		}))
+		Expect(node.Labels).ToNot(HaveKey(v1.NodeRegisteredLabelKey))
+		Expect(node.Spec.Taints).To(ContainElement(v1.UnregisteredNoExecuteTaint))
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsUnknown()).To(BeTrue())
+	})
+	Context("Registration Attempts Metric", func() {
+		BeforeEach(func() {
+			nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal.Reset()
+		})
+		It("should record a registered attempt when the Node comes online", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "registered",
+			})
+		})
+		It("should record a node_not_found attempt when the NodeClaim has launched but the Node hasn't come online", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "node_not_found",
+			})
+		})
+		It("should record a multiple_nodes attempt when multiple Nodes match the NodeClaim", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node1 := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			node2 := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node1, node2)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "multiple_nodes",
+			})
+		})
+		It("should record a not_launched attempt when the NodeClaim fails to launch", func() {
+			cloudProvider.NextCreateErr = fmt.Errorf("failed to launch")
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			_ = ExpectObjectReconcileFailed(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "not_launched",
+			})
+		})
+		It("should record a node_not_found attempt once while waiting for the Node to come online", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "node_not_found",
+			})
+		})
+		It("shouldn't record another node_not_found attempt when the Node is deleted before it can be synced", func() {
+			kubeClient := &deleteNodeBeforePatchClient{Client: env.Client}
+			controller := nodeclaimlifecycle.NewController(fakeClock, kubeClient, cloudProvider, recorder)
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClaim)
+
+			// The NodeClaim was already waiting on the Node before it came online, so it's still the same wait
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).Reason).To(Equal("NodeNotFound"))
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "node_not_found",
+			})
+		})
+		It("should record a sync_failed attempt when the Node can't be synced", func() {
+			kubeClient := &rejectNodeLabelsPatchClient{Client: env.Client}
+			controller := nodeclaimlifecycle.NewController(fakeClock, kubeClient, cloudProvider, recorder)
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			_ = ExpectObjectReconcileFailed(ctx, env.Client, controller, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "sync_failed",
+			})
+		})
+		It("should record a startup_taints_exist attempt when registration is waiting on startupTaints to be removed", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{RegistrationRequiresStartupTaintsCleared: lo.ToPtr(true)}))
+			DeferCleanup(func() {
+				ctx = options.ToContext(ctx, test.Options())
+			})
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+				Spec: v1.NodeClaimSpec{
+					StartupTaints: []corev1.Taint{{Key: "custom-startup-taint", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			// Reconciling again while still waiting on the startupTaints shouldn't record another attempt
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "startup_taints_exist",
+			})
+		})
+		It("should record a hook_failed attempt when the CloudProvider OnRegistered hook fails", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			cloudProvider.NextOnRegisteredErr = fmt.Errorf("failed to register")
+			_ = ExpectObjectReconcileFailed(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			ExpectMetricCounterValue(nodeclaimlifecycle.NodeClaimRegistrationAttemptsTotal, 1, map[string]string{
+				"nodepool": nodePool.Name,
+				"result":   "hook_failed",
+			})
+		})
+	})
+	Context("Memory Allocatable Ratio Metric", func() {
+		BeforeEach(func() {
+			nodeclaimlifecycle.NodeClaimMemoryAllocatableRatio.Reset()
+		})
+		It("should observe the ratio of the observed to the estimated memory allocatable", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			nodeClaim.Status.Allocatable = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")}
+			ExpectApplied(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("7Gi")}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			m, ok := FindMetricWithLabelValues("karpenter_nodeclaims_memory_allocatable_ratio", map[string]string{"nodepool": nodePool.Name})
+			Expect(ok).To(BeTrue())
+			Expect(m.GetHistogram().GetSampleCount()).To(BeNumerically("==", 1))
+			Expect(m.GetHistogram().GetSampleSum()).To(BeNumerically("~", 0.875))
+		})
+		It("should not observe a ratio when the NodeClaim has no memory estimate", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			nodeClaim.Status.Allocatable = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+			ExpectApplied(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("7Gi")}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			_, ok := FindMetricWithLabelValues("karpenter_nodeclaims_memory_allocatable_ratio", map[string]string{"nodepool": nodePool.Name})
+			Expect(ok).To(BeFalse())
+		})
+	})
+	Context("AllocatableDiffAnnotation", func() {
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{EnableAllocatableDiffAnnotation: lo.ToPtr(true)}))
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		DescribeTable("should annotate the NodeClaim with the difference between the estimated and observed allocatable",
+			func(observed corev1.ResourceList, expected string) {
+				nodeClaim := test.NodeClaim(v1.NodeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							v1.NodePoolLabelKey: nodePool.Name,
+						},
+					},
+				})
+				ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+				ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+				nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+				nodeClaim.Status.Allocatable = corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("7600Mi"),
+				}
+				ExpectApplied(ctx, env.Client, nodeClaim)
+
+				node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}, Allocatable: observed})
+				ExpectApplied(ctx, env.Client, node)
+				ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+				nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+				Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+				if expected == "" {
+					Expect(nodeClaim.Annotations).ToNot(HaveKey(v1.AllocatableDiffAnnotationKey))
+				} else {
+					Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.AllocatableDiffAnnotationKey, expected))
+				}
+			},
+			Entry("when a single resource differs",
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("7200Mi")},
+				"memory: est 7600Mi, obs 7200Mi (-5.3%)",
+			),
+			Entry("when multiple resources differ",
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1930m"), corev1.ResourceMemory: resource.MustParse("7200Mi")},
+				"cpu: est 2, obs 1930m (-3.5%); memory: est 7600Mi, obs 7200Mi (-5.3%)",
+			),
+			Entry("when the observed allocatable is higher than the estimate",
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("7980Mi")},
+				"memory: est 7600Mi, obs 7980Mi (+5.0%)",
+			),
+			Entry("unless no resources differ",
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("7600Mi")},
+				"",
+			),
+		)
+		It("should not annotate the NodeClaim when the option is disabled", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			nodeClaim.Status.Allocatable = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("7600Mi")}
+			ExpectApplied(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("7200Mi")}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).ToNot(HaveKey(v1.AllocatableDiffAnnotationKey))
+		})
+	})
+	Context("RegistrationRequiresStartupTaintsCleared", func() {
+		var startupTaint corev1.Taint
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{RegistrationRequiresStartupTaintsCleared: lo.ToPtr(true)}))
+			startupTaint = corev1.Taint{
+				Key:    "custom-startup-taint",
+				Effect: corev1.TaintEffectNoSchedule,
+				Value:  "custom-startup-value",
+			}
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		It("should wait to register the NodeClaim until the startupTaints are removed from the Node", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+				Spec: v1.NodeClaimSpec{
+					StartupTaints: []corev1.Taint{startupTaint},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			// The startupTaints are synced, but registration waits until they are removed
+			node = ExpectExists(ctx, env.Client, node)
+			Expect(node.Spec.Taints).To(ContainElement(startupTaint))
+			Expect(node.Spec.Taints).ToNot(ContainElement(v1.UnregisteredNoExecuteTaint))
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsUnknown()).To(BeTrue())
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).Reason).To(Equal("StartupTaintsExist"))
+			Expect(nodeClaim.Status.NodeName).To(BeEmpty())
+
+			// Reconciling again shouldn't complete registration while the startupTaint lingers
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsUnknown()).To(BeTrue())
+
+			node = ExpectExists(ctx, env.Client, node)
+			node.Spec.Taints = []corev1.Taint{}
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+			Expect(nodeClaim.Status.NodeName).To(Equal(node.Name))
+			// The startupTaints shouldn't be re-synced once they've been removed
+			node = ExpectExists(ctx, env.Client, node)
+			Expect(node.Spec.Taints).ToNot(ContainElement(startupTaint))
+		})
+		It("shouldn't delete the NodeClaim past the registration ttl while waiting for the startupTaints to be removed", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+				Spec: v1.NodeClaimSpec{
+					StartupTaints: []corev1.Taint{startupTaint},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).Reason).To(Equal("StartupTaintsExist"))
+
+			// The startupTaints take longer than the registration ttl to be removed
+			fakeClock.Step(time.Minute * 20)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsUnknown()).To(BeTrue())
+
+			node = ExpectExists(ctx, env.Client, node)
+			node.Spec.Taints = []corev1.Taint{}
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+		})
+		It("should delete the NodeClaim when the startupTaints aren't removed before the instance is gone", func() {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+				Spec: v1.NodeClaimSpec{
+					StartupTaints: []corev1.Taint{startupTaint},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).Reason).To(Equal("StartupTaintsExist"))
+
+			// The instance goes away while its startupTaints are still on the Node, so they're never removed
+			Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+			fakeClock.Step(time.Hour)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+		})
+		It("should register the NodeClaim immediately when the option is disabled", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+				Spec: v1.NodeClaimSpec{
+					StartupTaints: []corev1.Taint{startupTaint},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+			node = ExpectExists(ctx, env.Client, node)
+			Expect(node.Spec.Taints).To(ContainElement(startupTaint))
+		})
+	})
 })
+
+// deleteNodeBeforePatchClient deletes any Node right before patching it, simulating the Node being removed by
+// another controller between when it's fetched and when it's patched
+type deleteNodeBeforePatchClient struct {
+	client.Client
+}
+
+func (c *deleteNodeBeforePatchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if _, ok := obj.(*corev1.Node); ok {
+		ExpectDeleted(ctx, c.Client, obj.DeepCopyObject().(client.Object))
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// rejectNodeLabelsPatchClient rejects any Node patch that updates labels, simulating a label sync that's denied by
+// validation or an admission webhook
+type rejectNodeLabelsPatchClient struct {
+	client.Client
+}
+
+func (c *rejectNodeLabelsPatchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if _, ok := obj.(*corev1.Node); ok {
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(data), `"labels"`) {
+			return apierrors.NewForbidden(corev1.Resource("nodes"), obj.GetName(), fmt.Errorf("labels can't be updated"))
+		}
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
@@ -31,7 +31,9 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
 )
@@ -57,9 +59,11 @@ func (c *Controller) Reconcile(ctx context.Context, np *v1.NodePool) (reconcile.
 		return reconcile.Result{}, nil
 	}
 
+	ReconcilesTotal.Inc(map[string]string{metrics.NodePoolLabel: np.Name})
 	stored := np.DeepCopy()
 
-	if np.Annotations[v1.NodePoolHashVersionAnnotationKey] != v1.NodePoolHashVersion {
+	// NodeClaims are lazily re-hashed by the nodeclaim disruption controller when re-hashing is deferred
+	if np.Annotations[v1.NodePoolHashVersionAnnotationKey] != v1.NodePoolHashVersion && !options.FromContext(ctx).DeferNodeClaimRehash {
 		if err := c.updateNodeClaimHash(ctx, np); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -90,6 +94,7 @@ func (c *Controller) Register(_ context.Context, m manager.Manager) error {
 // NodePool. Since, we cannot rely on the `nodepool-hash` on the NodeClaims, due to the breaking change, we will need to re-calculate the hash and update the annotation.
 // For more information on the Drift Hash Versioning: https://github.com/kubernetes-sigs/karpenter/blob/main/designs/drift-hash-versioning.md
 func (c *Controller) updateNodeClaimHash(ctx context.Context, np *v1.NodePool) error {
+	defer metrics.Measure(NodeClaimRehashDurationSeconds, map[string]string{metrics.NodePoolLabel: np.Name})()
 	nodeClaims, err := nodeclaimutils.ListManaged(ctx, c.kubeClient, c.cloudProvider, nodeclaimutils.ForNodePool(np.Name))
 	if err != nil {
 		return err
@@ -98,27 +103,44 @@ func (c *Controller) updateNodeClaimHash(ctx context.Context, np *v1.NodePool) e
 	errs := make([]error, len(nodeClaims))
 	for i, nc := range nodeClaims {
 		stored := nc.DeepCopy()
-
-		if nc.Annotations[v1.NodePoolHashVersionAnnotationKey] != v1.NodePoolHashVersion {
-			nc.Annotations = lo.Assign(nc.Annotations, map[string]string{
-				v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
-			})
-
-			// Any NodeClaim that is already drifted will remain drifted if the karpenter.sh/nodepool-hash-version doesn't match
-			// Since the hashing mechanism has changed we will not be able to determine if the drifted status of the NodeClaim has changed
-			if nc.StatusConditions().Get(v1.ConditionTypeDrifted) == nil {
-				nc.Annotations = lo.Assign(nc.Annotations, map[string]string{
-					v1.NodePoolHashAnnotationKey: np.Hash(),
-				})
-			}
-
-			if !equality.Semantic.DeepEqual(stored, nc) {
-				if err := c.kubeClient.Patch(ctx, nc, client.MergeFrom(stored)); err != nil {
-					errs[i] = client.IgnoreNotFound(err)
-				}
-			}
+		if !UpdateNodeClaimHash(np, nc) {
+			continue
+		}
+		if err := c.kubeClient.Patch(ctx, nc, client.MergeFrom(stored)); err != nil {
+			errs[i] = client.IgnoreNotFound(err)
+			continue
 		}
+		RecordNodeClaimRehash(np, nc)
 	}
 
 	return multierr.Combine(errs...)
 }
+
+// UpdateNodeClaimHash updates the hash annotations on the NodeClaim to match the NodePool's current hash version. This
+// returns true if the NodeClaim's annotations were modified and need to be patched.
+func UpdateNodeClaimHash(np *v1.NodePool, nc *v1.NodeClaim) bool {
+	if nc.Annotations[v1.NodePoolHashVersionAnnotationKey] == v1.NodePoolHashVersion {
+		return false
+	}
+	nc.Annotations = lo.Assign(nc.Annotations, map[string]string{
+		v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
+	})
+	// Any NodeClaim that is already drifted will remain drifted if the karpenter.sh/nodepool-hash-version doesn't match
+	// Since the hashing mechanism has changed we will not be able to determine if the drifted status of the NodeClaim has changed
+	if nc.StatusConditions().Get(v1.ConditionTypeDrifted) == nil {
+		nc.Annotations = lo.Assign(nc.Annotations, map[string]string{
+			v1.NodePoolHashAnnotationKey: np.Hash(),
+		})
+	}
+	return true
+}
+
+// RecordNodeClaimRehash records that the NodeClaim was re-hashed after a drift hash version change. NodeClaims that
+// are already drifted are counted separately since their hash is left as-is.
+func RecordNodeClaimRehash(np *v1.NodePool, nc *v1.NodeClaim) {
+	if nc.StatusConditions().Get(v1.ConditionTypeDrifted) == nil {
+		NodeClaimRehashTotal.Inc(map[string]string{metrics.NodePoolLabel: np.Name})
+	} else {
+		NodeClaimRehashDriftedTotal.Inc(map[string]string{metrics.NodePoolLabel: np.Name})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hash
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/samber/lo"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// Diff returns the JSON paths of the fields that contribute to the NodePool drift hash and differ between the two
+// NodePools, e.g. "spec.template.spec.nodeClassRef.name". Fields that are ignored by NodePool.Hash() are never
+// reported, so an empty result means that both NodePools produce the same drift hash.
+func Diff(a, b *v1.NodePool) []string {
+	if a.Hash() == b.Hash() {
+		return nil
+	}
+	return diff("spec.template", reflect.ValueOf(a.Spec.Template), reflect.ValueOf(b.Spec.Template))
+}
+
+func diff(path string, a, b reflect.Value) []string {
+	if !a.IsValid() || !b.IsValid() || fieldHash(a) == fieldHash(b) {
+		return nil
+	}
+	// Descend through pointers to structs set on both sides so that we can report the specific field that changed
+	if a.Kind() == reflect.Pointer && b.Kind() == reflect.Pointer && !a.IsNil() && !b.IsNil() {
+		return diff(path, a.Elem(), b.Elem())
+	}
+	// Types with custom JSON serialization are treated as a single value since their fields don't map to JSON paths
+	if a.Kind() != reflect.Struct || a.Type().Implements(jsonMarshalerType) || reflect.PointerTo(a.Type()).Implements(jsonMarshalerType) {
+		return []string{path}
+	}
+	var paths []string
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("hash") == "ignore" {
+			continue
+		}
+		name := lo.Ternary(field.Tag.Get("json") != "", strings.Split(field.Tag.Get("json"), ",")[0], field.Name)
+		paths = append(paths, diff(path+"."+name, a.Field(i), b.Field(i))...)
+	}
+	return paths
+}
+
+func fieldHash(v reflect.Value) uint64 {
+	if !v.CanInterface() {
+		return 0
+	}
+	return lo.Must(hashstructure.Hash(v.Interface(), hashstructure.FormatV2, v1.NodePoolHashOptions()))
+}
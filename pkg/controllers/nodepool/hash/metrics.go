@@ -0,0 +1,69 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hash
+
+import (
+	opmetrics "github.com/awslabs/operatorpkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/karpenter/pkg/metrics"
+)
+
+var (
+	ReconcilesTotal = opmetrics.NewPrometheusCounter(
+		crmetrics.Registry,
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodePoolSubsystem,
+			Name:      "hash_reconciles_total",
+			Help:      "Number of times the drift hash was reconciled for a NodePool. Labeled by nodepool.",
+		},
+		[]string{metrics.NodePoolLabel},
+	)
+	NodeClaimRehashTotal = opmetrics.NewPrometheusCounter(
+		crmetrics.Registry,
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodeClaimSubsystem,
+			Name:      "rehash_total",
+			Help:      "Number of NodeClaims whose drift hash was re-calculated after a drift hash version change. Labeled by nodepool.",
+		},
+		[]string{metrics.NodePoolLabel},
+	)
+	NodeClaimRehashDriftedTotal = opmetrics.NewPrometheusCounter(
+		crmetrics.Registry,
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodeClaimSubsystem,
+			Name:      "rehash_drifted_total",
+			Help:      "Number of NodeClaims that were left drifted instead of having their drift hash re-calculated after a drift hash version change. Labeled by nodepool.",
+		},
+		[]string{metrics.NodePoolLabel},
+	)
+	NodeClaimRehashDurationSeconds = opmetrics.NewPrometheusHistogram(
+		crmetrics.Registry,
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodePoolSubsystem,
+			Name:      "nodeclaim_rehash_duration_seconds",
+			Help:      "Duration of re-hashing a NodePool's NodeClaims after a drift hash version change in seconds. Labeled by nodepool.",
+			Buckets:   metrics.DurationBuckets(),
+		},
+		[]string{metrics.NodePoolLabel},
+	)
+)
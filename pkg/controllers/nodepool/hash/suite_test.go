@@ -32,6 +32,7 @@ import (
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/hash"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
@@ -51,6 +52,7 @@ func TestAPIs(t *testing.T) {
 
 var _ = BeforeSuite(func() {
 	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
+	ctx = options.ToContext(ctx, test.Options())
 	cp = fake.NewCloudProvider()
 	nodePoolController = hash.NewController(env.Client, cp)
 })
@@ -198,6 +200,36 @@ var _ = Describe("Static Drift Hash", func() {
 		Expect(nodeClaimTwo.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, expectedHash))
 		Expect(nodeClaimTwo.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
 	})
+	It("should not update nodepool hash versions on nodeclaims when nodeclaim re-hashing is deferred", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{DeferNodeClaimRehash: lo.ToPtr(true)}))
+		DeferCleanup(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		nodePool.Annotations = map[string]string{
+			v1.NodePoolHashAnnotationKey:        "abceduefed",
+			v1.NodePoolHashVersionAnnotationKey: "test",
+		}
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+				Annotations: map[string]string{
+					v1.NodePoolHashAnnotationKey:        "123456",
+					v1.NodePoolHashVersionAnnotationKey: "test",
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		// Expect the NodePool to have been re-hashed without its NodeClaims
+		Expect(nodePool.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, nodePool.Hash()))
+		Expect(nodePool.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
+		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, "123456"))
+		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, "test"))
+	})
 	It("should not update nodepool hash on all nodeclaims when the hash versions match the controller hash version", func() {
 		nodePool.Annotations = map[string]string{
 			v1.NodePoolHashAnnotationKey:        "abceduefed",
@@ -250,4 +282,142 @@ var _ = Describe("Static Drift Hash", func() {
 		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, "123456"))
 		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
 	})
+	Context("Metrics", func() {
+		BeforeEach(func() {
+			hash.ReconcilesTotal.Reset()
+			hash.NodeClaimRehashDurationSeconds.Reset()
+			hash.NodeClaimRehashTotal.Reset()
+			hash.NodeClaimRehashDriftedTotal.Reset()
+		})
+		It("should record a reconcile without re-hashing NodeClaims when the hash version matches", func() {
+			nodePool.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "abceduefed",
+				v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
+			}
+			ExpectApplied(ctx, env.Client, nodePool)
+
+			ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+			ExpectMetricCounterValue(hash.ReconcilesTotal, 1, map[string]string{"nodepool": nodePool.Name})
+			_, ok := FindMetricWithLabelValues("karpenter_nodepools_nodeclaim_rehash_duration_seconds", map[string]string{"nodepool": nodePool.Name})
+			Expect(ok).To(BeFalse())
+		})
+		It("should record the NodeClaim re-hash duration when the hash version changes", func() {
+			nodePool.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "abceduefed",
+				v1.NodePoolHashVersionAnnotationKey: "test",
+			}
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+					Annotations: map[string]string{
+						v1.NodePoolHashAnnotationKey:        "123456",
+						v1.NodePoolHashVersionAnnotationKey: "test",
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+			ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+			ExpectMetricCounterValue(hash.ReconcilesTotal, 1, map[string]string{"nodepool": nodePool.Name})
+			ExpectMetricHistogramSampleCountValue("karpenter_nodepools_nodeclaim_rehash_duration_seconds", 1, map[string]string{"nodepool": nodePool.Name})
+
+			// Once the NodePool has been migrated, we shouldn't re-hash its NodeClaims again
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+			ExpectMetricCounterValue(hash.ReconcilesTotal, 2, map[string]string{"nodepool": nodePool.Name})
+			ExpectMetricHistogramSampleCountValue("karpenter_nodepools_nodeclaim_rehash_duration_seconds", 1, map[string]string{"nodepool": nodePool.Name})
+		})
+		It("should count the NodeClaims that were re-hashed and left drifted when the hash version changes", func() {
+			nodePool.Annotations = map[string]string{
+				v1.NodePoolHashAnnotationKey:        "abceduefed",
+				v1.NodePoolHashVersionAnnotationKey: "test",
+			}
+			nodeClaims := lo.Times(5, func(_ int) *v1.NodeClaim {
+				return test.NodeClaim(v1.NodeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+						Annotations: map[string]string{
+							v1.NodePoolHashAnnotationKey:        "123456",
+							v1.NodePoolHashVersionAnnotationKey: "test",
+						},
+					},
+				})
+			})
+			nodeClaims[0].StatusConditions().SetTrue(v1.ConditionTypeDrifted)
+			nodeClaims[1].StatusConditions().SetTrue(v1.ConditionTypeDrifted)
+			// NodeClaims that are already on the current hash version shouldn't be counted
+			nodeClaims[2].Annotations[v1.NodePoolHashVersionAnnotationKey] = v1.NodePoolHashVersion
+			ExpectApplied(ctx, env.Client, nodePool)
+			for _, nc := range nodeClaims {
+				ExpectApplied(ctx, env.Client, nc)
+			}
+
+			ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+			ExpectMetricCounterValue(hash.NodeClaimRehashTotal, 2, map[string]string{"nodepool": nodePool.Name})
+			ExpectMetricCounterValue(hash.NodeClaimRehashDriftedTotal, 2, map[string]string{"nodepool": nodePool.Name})
+		})
+	})
+})
+
+var _ = Describe("Diff", func() {
+	var nodePool *v1.NodePool
+	BeforeEach(func() {
+		nodePool = test.NodePool(v1.NodePool{
+			Spec: v1.NodePoolSpec{
+				Template: v1.NodeClaimTemplate{
+					ObjectMeta: v1.ObjectMeta{
+						Annotations: map[string]string{"keyAnnotation": "valueAnnotation"},
+						Labels:      map[string]string{"keyLabel": "valueLabel"},
+					},
+					Spec: v1.NodeClaimTemplateSpec{
+						Taints:                 []corev1.Taint{{Key: "key", Effect: corev1.TaintEffectNoExecute}},
+						StartupTaints:          []corev1.Taint{{Key: "key", Effect: corev1.TaintEffectNoExecute}},
+						TerminationGracePeriod: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+			},
+		})
+	})
+	It("should not return any fields when the NodePools are the same", func() {
+		Expect(hash.Diff(nodePool, nodePool.DeepCopy())).To(BeEmpty())
+	})
+	DescribeTable("should return the hashed fields that differ",
+		func(update func(*v1.NodePool), expected ...string) {
+			updatedNodePool := nodePool.DeepCopy()
+			update(updatedNodePool)
+			Expect(hash.Diff(nodePool, updatedNodePool)).To(ConsistOf(expected))
+		},
+		Entry("Annotations", func(np *v1.NodePool) {
+			np.Spec.Template.Annotations = map[string]string{"keyAnnotation": "valueAnnotationChanged"}
+		}, "spec.template.metadata.annotations"),
+		Entry("Labels", func(np *v1.NodePool) { np.Spec.Template.Labels = map[string]string{"keyLabel": "valueLabelChanged"} }, "spec.template.metadata.labels"),
+		Entry("Taints", func(np *v1.NodePool) { np.Spec.Template.Spec.Taints[0].Key = "keyChanged" }, "spec.template.spec.taints"),
+		Entry("StartupTaints", func(np *v1.NodePool) { np.Spec.Template.Spec.StartupTaints[0].Key = "keyChanged" }, "spec.template.spec.startupTaints"),
+		Entry("NodeClassRef Name", func(np *v1.NodePool) { np.Spec.Template.Spec.NodeClassRef.Name = "nodeClassChanged" }, "spec.template.spec.nodeClassRef.name"),
+		Entry("TerminationGracePeriod", func(np *v1.NodePool) {
+			np.Spec.Template.Spec.TerminationGracePeriod = &metav1.Duration{Duration: 10 * time.Minute}
+		}, "spec.template.spec.terminationGracePeriod"),
+		Entry("ExpireAfter", func(np *v1.NodePool) { np.Spec.Template.Spec.ExpireAfter = v1.MustParseNillableDuration("1h") }, "spec.template.spec.expireAfter"),
+		Entry("Multiple Fields", func(np *v1.NodePool) {
+			np.Spec.Template.Labels = map[string]string{"keyLabel": "valueLabelChanged"}
+			np.Spec.Template.Spec.Taints[0].Key = "keyChanged"
+		}, "spec.template.metadata.labels", "spec.template.spec.taints"),
+	)
+	DescribeTable("should not return fields that are ignored by the hash",
+		func(update func(*v1.NodePool)) {
+			updatedNodePool := nodePool.DeepCopy()
+			update(updatedNodePool)
+			Expect(hash.Diff(nodePool, updatedNodePool)).To(BeEmpty())
+		},
+		Entry("Requirements", func(np *v1.NodePool) {
+			np.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test"}}},
+			}
+		}),
+		Entry("Weight", func(np *v1.NodePool) { np.Spec.Weight = lo.ToPtr[int32](10) }),
+		Entry("Limits", func(np *v1.NodePool) {
+			np.Spec.Limits = v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")})
+		}),
+		Entry("ConsolidateAfter", func(np *v1.NodePool) { np.Spec.Disruption.ConsolidateAfter = v1.MustParseNillableDuration("30s") }),
+	)
 })
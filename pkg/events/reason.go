@@ -46,4 +46,7 @@ const (
 	InsufficientCapacityError = "InsufficientCapacityError"
 	UnregisteredTaintMissing  = "UnregisteredTaintMissing"
 	NodeClassNotReady         = "NodeClassNotReady"
+	Registered                = "Registered"
+	TaintsOutOfSync           = "TaintsOutOfSync"
+	NodePartiallySynced       = "NodePartiallySynced"
 )
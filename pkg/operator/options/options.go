@@ -36,6 +36,9 @@ var (
 	Injectables = []Injectable{&Options{}}
 )
 
+// DeferredNodeClaimRehashRate is the number of NodeClaims per second that are re-hashed when re-hashing is deferred
+const DeferredNodeClaimRehashRate = 10
+
 type optionsKey struct{}
 
 type FeatureGates struct {
@@ -48,22 +51,29 @@ type FeatureGates struct {
 
 // Options contains all CLI flags / env vars for karpenter-core. It adheres to the options.Injectable interface.
 type Options struct {
-	ServiceName             string
-	MetricsPort             int
-	HealthProbePort         int
-	KubeClientQPS           int
-	KubeClientBurst         int
-	EnableProfiling         bool
-	DisableLeaderElection   bool
-	LeaderElectionName      string
-	LeaderElectionNamespace string
-	MemoryLimit             int64
-	LogLevel                string
-	LogOutputPaths          string
-	LogErrorOutputPaths     string
-	BatchMaxDuration        time.Duration
-	BatchIdleDuration       time.Duration
-	FeatureGates            FeatureGates
+	ServiceName                              string
+	MetricsPort                              int
+	HealthProbePort                          int
+	KubeClientQPS                            int
+	KubeClientBurst                          int
+	EnableProfiling                          bool
+	DisableLeaderElection                    bool
+	LeaderElectionName                       string
+	LeaderElectionNamespace                  string
+	MemoryLimit                              int64
+	LogLevel                                 string
+	LogOutputPaths                           string
+	LogErrorOutputPaths                      string
+	BatchMaxDuration                         time.Duration
+	BatchIdleDuration                        time.Duration
+	GCWebhookURL                             string
+	GCWebhookStrict                          bool
+	EnableAllocatableDiffAnnotation          bool
+	RegistrationRequiresStartupTaintsCleared bool
+	DeferNodeClaimRehash                     bool
+	GCOnUnknownReady                         bool
+	GCUnknownReadyDuration                   time.Duration
+	FeatureGates                             FeatureGates
 }
 
 type FlagSet struct {
@@ -99,6 +109,13 @@ func (o *Options) AddFlags(fs *FlagSet) {
 	fs.StringVar(&o.LogErrorOutputPaths, "log-error-output-paths", env.WithDefaultString("LOG_ERROR_OUTPUT_PATHS", "stderr"), "Optional comma separated paths for logging error output")
 	fs.DurationVar(&o.BatchMaxDuration, "batch-max-duration", env.WithDefaultDuration("BATCH_MAX_DURATION", 10*time.Second), "The maximum length of a batch window. The longer this is, the more pods we can consider for provisioning at one time which usually results in fewer but larger nodes.")
 	fs.DurationVar(&o.BatchIdleDuration, "batch-idle-duration", env.WithDefaultDuration("BATCH_IDLE_DURATION", time.Second), "The maximum amount of time with no new pending pods that if exceeded ends the current batching window. If pods arrive faster than this time, the batching window will be extended up to the maxDuration. If they arrive slower, the pods will be batched separately.")
+	fs.StringVar(&o.GCWebhookURL, "gc-webhook-url", env.WithDefaultString("GC_WEBHOOK_URL", ""), "Optional URL that the garbage collection controller POSTs a summary of NodeClaims to before deleting them")
+	fs.BoolVarWithEnv(&o.GCWebhookStrict, "gc-webhook-strict", "GC_WEBHOOK_STRICT", false, "Require a 2xx response from the gc-webhook-url before the garbage collection controller deletes NodeClaims")
+	fs.BoolVarWithEnv(&o.EnableAllocatableDiffAnnotation, "enable-allocatable-diff-annotation", "ENABLE_ALLOCATABLE_DIFF_ANNOTATION", false, "Annotate NodeClaims at registration with the difference between their estimated allocatable and the allocatable reported by the Node")
+	fs.BoolVarWithEnv(&o.RegistrationRequiresStartupTaintsCleared, "registration-requires-startup-taints-cleared", "REGISTRATION_REQUIRES_STARTUP_TAINTS_CLEARED", false, "Hold NodeClaim registration until the NodeClaim's startup taints have been removed from the Node. NodeClaims that are only waiting on startup taints are given an hour from launch to register instead of the 15 minute registration TTL.")
+	fs.BoolVarWithEnv(&o.DeferNodeClaimRehash, "defer-nodeclaim-rehash", "DEFER_NODECLAIM_REHASH", false, fmt.Sprintf("Skip re-hashing all of a NodePool's NodeClaims when the drift hash version changes and instead re-hash each NodeClaim when it is next reconciled. Deferred re-hashes are rate limited to %d NodeClaims per second.", DeferredNodeClaimRehashRate))
+	fs.BoolVarWithEnv(&o.GCOnUnknownReady, "gc-on-unknown-ready", "GC_ON_UNKNOWN_READY", false, "Allow the garbage collection controller to delete NodeClaims whose instance is gone when their Node's Ready condition is Unknown. By default, these Nodes are treated as Ready and left alone")
+	fs.DurationVar(&o.GCUnknownReadyDuration, "gc-unknown-ready-duration", env.WithDefaultDuration("GC_UNKNOWN_READY_DURATION", 0), "The amount of time a Node's Ready condition must be Unknown before the garbage collection controller deletes its NodeClaim when the instance is gone. Only applies when gc-on-unknown-ready is enabled")
 	fs.StringVar(&o.FeatureGates.inputStr, "feature-gates", env.WithDefaultString("FEATURE_GATES", "NodeRepair=false,ReservedCapacity=false,SpotToSpotConsolidation=false"), "Optional features can be enabled / disabled using feature gates. Current options are: NodeRepair, ReservedCapacity, and SpotToSpotConsolidation")
 }
 
@@ -58,6 +58,13 @@ var _ = Describe("Options", func() {
 		"LOG_ERROR_OUTPUT_PATHS",
 		"BATCH_MAX_DURATION",
 		"BATCH_IDLE_DURATION",
+		"GC_WEBHOOK_URL",
+		"GC_WEBHOOK_STRICT",
+		"ENABLE_ALLOCATABLE_DIFF_ANNOTATION",
+		"REGISTRATION_REQUIRES_STARTUP_TAINTS_CLEARED",
+		"DEFER_NODECLAIM_REHASH",
+		"GC_ON_UNKNOWN_READY",
+		"GC_UNKNOWN_READY_DURATION",
 		"FEATURE_GATES",
 	}
 
@@ -95,21 +102,28 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr(""),
-				MetricsPort:             lo.ToPtr(8080),
-				HealthProbePort:         lo.ToPtr(8081),
-				KubeClientQPS:           lo.ToPtr(200),
-				KubeClientBurst:         lo.ToPtr(300),
-				EnableProfiling:         lo.ToPtr(false),
-				DisableLeaderElection:   lo.ToPtr(false),
-				LeaderElectionName:      lo.ToPtr("karpenter-leader-election"),
-				LeaderElectionNamespace: lo.ToPtr(""),
-				MemoryLimit:             lo.ToPtr[int64](-1),
-				LogLevel:                lo.ToPtr("info"),
-				LogOutputPaths:          lo.ToPtr("stdout"),
-				LogErrorOutputPaths:     lo.ToPtr("stderr"),
-				BatchMaxDuration:        lo.ToPtr(10 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(time.Second),
+				ServiceName:                              lo.ToPtr(""),
+				MetricsPort:                              lo.ToPtr(8080),
+				HealthProbePort:                          lo.ToPtr(8081),
+				KubeClientQPS:                            lo.ToPtr(200),
+				KubeClientBurst:                          lo.ToPtr(300),
+				EnableProfiling:                          lo.ToPtr(false),
+				DisableLeaderElection:                    lo.ToPtr(false),
+				LeaderElectionName:                       lo.ToPtr("karpenter-leader-election"),
+				LeaderElectionNamespace:                  lo.ToPtr(""),
+				MemoryLimit:                              lo.ToPtr[int64](-1),
+				LogLevel:                                 lo.ToPtr("info"),
+				LogOutputPaths:                           lo.ToPtr("stdout"),
+				LogErrorOutputPaths:                      lo.ToPtr("stderr"),
+				BatchMaxDuration:                         lo.ToPtr(10 * time.Second),
+				BatchIdleDuration:                        lo.ToPtr(time.Second),
+				GCWebhookURL:                             lo.ToPtr(""),
+				GCWebhookStrict:                          lo.ToPtr(false),
+				EnableAllocatableDiffAnnotation:          lo.ToPtr(false),
+				RegistrationRequiresStartupTaintsCleared: lo.ToPtr(false),
+				DeferNodeClaimRehash:                     lo.ToPtr(false),
+				GCOnUnknownReady:                         lo.ToPtr(false),
+				GCUnknownReadyDuration:                   lo.ToPtr(time.Duration(0)),
 				FeatureGates: test.FeatureGates{
 					ReservedCapacity:        lo.ToPtr(false),
 					NodeRepair:              lo.ToPtr(false),
@@ -138,25 +152,39 @@ var _ = Describe("Options", func() {
 				"--log-error-output-paths", "/etc/k8s/testerror",
 				"--batch-max-duration", "5s",
 				"--batch-idle-duration", "5s",
+				"--gc-webhook-url", "https://cli.example.com/gc",
+				"--gc-webhook-strict",
+				"--enable-allocatable-diff-annotation",
+				"--registration-requires-startup-taints-cleared",
+				"--defer-nodeclaim-rehash",
+				"--gc-on-unknown-ready",
+				"--gc-unknown-ready-duration", "5m",
 				"--feature-gates", "ReservedCapacity=true,SpotToSpotConsolidation=true,NodeRepair=true",
 			)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr("cli"),
-				MetricsPort:             lo.ToPtr(0),
-				HealthProbePort:         lo.ToPtr(0),
-				KubeClientQPS:           lo.ToPtr(0),
-				KubeClientBurst:         lo.ToPtr(0),
-				EnableProfiling:         lo.ToPtr(true),
-				DisableLeaderElection:   lo.ToPtr(true),
-				LeaderElectionName:      lo.ToPtr("karpenter-controller"),
-				LeaderElectionNamespace: lo.ToPtr("karpenter"),
-				MemoryLimit:             lo.ToPtr[int64](0),
-				LogLevel:                lo.ToPtr("debug"),
-				LogOutputPaths:          lo.ToPtr("/etc/k8s/test"),
-				LogErrorOutputPaths:     lo.ToPtr("/etc/k8s/testerror"),
-				BatchMaxDuration:        lo.ToPtr(5 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(5 * time.Second),
+				ServiceName:                              lo.ToPtr("cli"),
+				MetricsPort:                              lo.ToPtr(0),
+				HealthProbePort:                          lo.ToPtr(0),
+				KubeClientQPS:                            lo.ToPtr(0),
+				KubeClientBurst:                          lo.ToPtr(0),
+				EnableProfiling:                          lo.ToPtr(true),
+				DisableLeaderElection:                    lo.ToPtr(true),
+				LeaderElectionName:                       lo.ToPtr("karpenter-controller"),
+				LeaderElectionNamespace:                  lo.ToPtr("karpenter"),
+				MemoryLimit:                              lo.ToPtr[int64](0),
+				LogLevel:                                 lo.ToPtr("debug"),
+				LogOutputPaths:                           lo.ToPtr("/etc/k8s/test"),
+				LogErrorOutputPaths:                      lo.ToPtr("/etc/k8s/testerror"),
+				BatchMaxDuration:                         lo.ToPtr(5 * time.Second),
+				BatchIdleDuration:                        lo.ToPtr(5 * time.Second),
+				GCWebhookURL:                             lo.ToPtr("https://cli.example.com/gc"),
+				GCWebhookStrict:                          lo.ToPtr(true),
+				EnableAllocatableDiffAnnotation:          lo.ToPtr(true),
+				RegistrationRequiresStartupTaintsCleared: lo.ToPtr(true),
+				DeferNodeClaimRehash:                     lo.ToPtr(true),
+				GCOnUnknownReady:                         lo.ToPtr(true),
+				GCUnknownReadyDuration:                   lo.ToPtr(5 * time.Minute),
 				FeatureGates: test.FeatureGates{
 					ReservedCapacity:        lo.ToPtr(true),
 					NodeRepair:              lo.ToPtr(true),
@@ -181,6 +209,13 @@ var _ = Describe("Options", func() {
 			os.Setenv("LOG_ERROR_OUTPUT_PATHS", "/etc/k8s/testerror")
 			os.Setenv("BATCH_MAX_DURATION", "5s")
 			os.Setenv("BATCH_IDLE_DURATION", "5s")
+			os.Setenv("GC_WEBHOOK_URL", "https://env.example.com/gc")
+			os.Setenv("GC_WEBHOOK_STRICT", "true")
+			os.Setenv("ENABLE_ALLOCATABLE_DIFF_ANNOTATION", "true")
+			os.Setenv("REGISTRATION_REQUIRES_STARTUP_TAINTS_CLEARED", "true")
+			os.Setenv("DEFER_NODECLAIM_REHASH", "true")
+			os.Setenv("GC_ON_UNKNOWN_READY", "true")
+			os.Setenv("GC_UNKNOWN_READY_DURATION", "5m")
 			os.Setenv("FEATURE_GATES", "ReservedCapacity=true,SpotToSpotConsolidation=true,NodeRepair=true")
 			fs = &options.FlagSet{
 				FlagSet: flag.NewFlagSet("karpenter", flag.ContinueOnError),
@@ -189,21 +224,28 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr("env"),
-				MetricsPort:             lo.ToPtr(0),
-				HealthProbePort:         lo.ToPtr(0),
-				KubeClientQPS:           lo.ToPtr(0),
-				KubeClientBurst:         lo.ToPtr(0),
-				EnableProfiling:         lo.ToPtr(true),
-				DisableLeaderElection:   lo.ToPtr(true),
-				LeaderElectionName:      lo.ToPtr("karpenter-controller"),
-				LeaderElectionNamespace: lo.ToPtr("karpenter"),
-				MemoryLimit:             lo.ToPtr[int64](0),
-				LogLevel:                lo.ToPtr("debug"),
-				LogOutputPaths:          lo.ToPtr("/etc/k8s/test"),
-				LogErrorOutputPaths:     lo.ToPtr("/etc/k8s/testerror"),
-				BatchMaxDuration:        lo.ToPtr(5 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(5 * time.Second),
+				ServiceName:                              lo.ToPtr("env"),
+				MetricsPort:                              lo.ToPtr(0),
+				HealthProbePort:                          lo.ToPtr(0),
+				KubeClientQPS:                            lo.ToPtr(0),
+				KubeClientBurst:                          lo.ToPtr(0),
+				EnableProfiling:                          lo.ToPtr(true),
+				DisableLeaderElection:                    lo.ToPtr(true),
+				LeaderElectionName:                       lo.ToPtr("karpenter-controller"),
+				LeaderElectionNamespace:                  lo.ToPtr("karpenter"),
+				MemoryLimit:                              lo.ToPtr[int64](0),
+				LogLevel:                                 lo.ToPtr("debug"),
+				LogOutputPaths:                           lo.ToPtr("/etc/k8s/test"),
+				LogErrorOutputPaths:                      lo.ToPtr("/etc/k8s/testerror"),
+				BatchMaxDuration:                         lo.ToPtr(5 * time.Second),
+				BatchIdleDuration:                        lo.ToPtr(5 * time.Second),
+				GCWebhookURL:                             lo.ToPtr("https://env.example.com/gc"),
+				GCWebhookStrict:                          lo.ToPtr(true),
+				EnableAllocatableDiffAnnotation:          lo.ToPtr(true),
+				RegistrationRequiresStartupTaintsCleared: lo.ToPtr(true),
+				DeferNodeClaimRehash:                     lo.ToPtr(true),
+				GCOnUnknownReady:                         lo.ToPtr(true),
+				GCUnknownReadyDuration:                   lo.ToPtr(5 * time.Minute),
 				FeatureGates: test.FeatureGates{
 					ReservedCapacity:        lo.ToPtr(true),
 					NodeRepair:              lo.ToPtr(true),
@@ -236,21 +278,28 @@ var _ = Describe("Options", func() {
 			)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr("cli"),
-				MetricsPort:             lo.ToPtr(0),
-				HealthProbePort:         lo.ToPtr(0),
-				KubeClientQPS:           lo.ToPtr(0),
-				KubeClientBurst:         lo.ToPtr(0),
-				EnableProfiling:         lo.ToPtr(true),
-				DisableLeaderElection:   lo.ToPtr(true),
-				LeaderElectionName:      lo.ToPtr("karpenter-leader-election"),
-				LeaderElectionNamespace: lo.ToPtr(""),
-				MemoryLimit:             lo.ToPtr[int64](0),
-				LogLevel:                lo.ToPtr("debug"),
-				LogOutputPaths:          lo.ToPtr("/etc/k8s/test"),
-				LogErrorOutputPaths:     lo.ToPtr("/etc/k8s/testerror"),
-				BatchMaxDuration:        lo.ToPtr(5 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(5 * time.Second),
+				ServiceName:                              lo.ToPtr("cli"),
+				MetricsPort:                              lo.ToPtr(0),
+				HealthProbePort:                          lo.ToPtr(0),
+				KubeClientQPS:                            lo.ToPtr(0),
+				KubeClientBurst:                          lo.ToPtr(0),
+				EnableProfiling:                          lo.ToPtr(true),
+				DisableLeaderElection:                    lo.ToPtr(true),
+				LeaderElectionName:                       lo.ToPtr("karpenter-leader-election"),
+				LeaderElectionNamespace:                  lo.ToPtr(""),
+				MemoryLimit:                              lo.ToPtr[int64](0),
+				LogLevel:                                 lo.ToPtr("debug"),
+				LogOutputPaths:                           lo.ToPtr("/etc/k8s/test"),
+				LogErrorOutputPaths:                      lo.ToPtr("/etc/k8s/testerror"),
+				BatchMaxDuration:                         lo.ToPtr(5 * time.Second),
+				BatchIdleDuration:                        lo.ToPtr(5 * time.Second),
+				GCWebhookURL:                             lo.ToPtr(""),
+				GCWebhookStrict:                          lo.ToPtr(false),
+				EnableAllocatableDiffAnnotation:          lo.ToPtr(false),
+				RegistrationRequiresStartupTaintsCleared: lo.ToPtr(false),
+				DeferNodeClaimRehash:                     lo.ToPtr(false),
+				GCOnUnknownReady:                         lo.ToPtr(false),
+				GCUnknownReadyDuration:                   lo.ToPtr(time.Duration(0)),
 				FeatureGates: test.FeatureGates{
 					ReservedCapacity:        lo.ToPtr(true),
 					NodeRepair:              lo.ToPtr(true),
@@ -308,6 +357,13 @@ func expectOptionsMatch(optsA, optsB *options.Options) {
 	Expect(optsA.LogErrorOutputPaths).To(Equal(optsB.LogErrorOutputPaths))
 	Expect(optsA.BatchMaxDuration).To(Equal(optsB.BatchMaxDuration))
 	Expect(optsA.BatchIdleDuration).To(Equal(optsB.BatchIdleDuration))
+	Expect(optsA.GCWebhookURL).To(Equal(optsB.GCWebhookURL))
+	Expect(optsA.GCWebhookStrict).To(Equal(optsB.GCWebhookStrict))
+	Expect(optsA.EnableAllocatableDiffAnnotation).To(Equal(optsB.EnableAllocatableDiffAnnotation))
+	Expect(optsA.RegistrationRequiresStartupTaintsCleared).To(Equal(optsB.RegistrationRequiresStartupTaintsCleared))
+	Expect(optsA.DeferNodeClaimRehash).To(Equal(optsB.DeferNodeClaimRehash))
+	Expect(optsA.GCOnUnknownReady).To(Equal(optsB.GCOnUnknownReady))
+	Expect(optsA.GCUnknownReadyDuration).To(Equal(optsB.GCUnknownReadyDuration))
 	Expect(optsA.FeatureGates.ReservedCapacity).To(Equal(optsB.FeatureGates.ReservedCapacity))
 	Expect(optsA.FeatureGates.NodeRepair).To(Equal(optsB.FeatureGates.NodeRepair))
 	Expect(optsA.FeatureGates.SpotToSpotConsolidation).To(Equal(optsB.FeatureGates.SpotToSpotConsolidation))
@@ -28,22 +28,29 @@ import (
 
 type OptionsFields struct {
 	// Vendor Neutral
-	ServiceName             *string
-	MetricsPort             *int
-	HealthProbePort         *int
-	KubeClientQPS           *int
-	KubeClientBurst         *int
-	EnableProfiling         *bool
-	DisableLeaderElection   *bool
-	LeaderElectionName      *string
-	LeaderElectionNamespace *string
-	MemoryLimit             *int64
-	LogLevel                *string
-	LogOutputPaths          *string
-	LogErrorOutputPaths     *string
-	BatchMaxDuration        *time.Duration
-	BatchIdleDuration       *time.Duration
-	FeatureGates            FeatureGates
+	ServiceName                              *string
+	MetricsPort                              *int
+	HealthProbePort                          *int
+	KubeClientQPS                            *int
+	KubeClientBurst                          *int
+	EnableProfiling                          *bool
+	DisableLeaderElection                    *bool
+	LeaderElectionName                       *string
+	LeaderElectionNamespace                  *string
+	MemoryLimit                              *int64
+	LogLevel                                 *string
+	LogOutputPaths                           *string
+	LogErrorOutputPaths                      *string
+	BatchMaxDuration                         *time.Duration
+	BatchIdleDuration                        *time.Duration
+	GCWebhookURL                             *string
+	GCWebhookStrict                          *bool
+	EnableAllocatableDiffAnnotation          *bool
+	RegistrationRequiresStartupTaintsCleared *bool
+	DeferNodeClaimRehash                     *bool
+	GCOnUnknownReady                         *bool
+	GCUnknownReadyDuration                   *time.Duration
+	FeatureGates                             FeatureGates
 }
 
 type FeatureGates struct {
@@ -61,19 +68,26 @@ func Options(overrides ...OptionsFields) *options.Options {
 	}
 
 	return &options.Options{
-		ServiceName:           lo.FromPtrOr(opts.ServiceName, ""),
-		MetricsPort:           lo.FromPtrOr(opts.MetricsPort, 8080),
-		HealthProbePort:       lo.FromPtrOr(opts.HealthProbePort, 8081),
-		KubeClientQPS:         lo.FromPtrOr(opts.KubeClientQPS, 200),
-		KubeClientBurst:       lo.FromPtrOr(opts.KubeClientBurst, 300),
-		EnableProfiling:       lo.FromPtrOr(opts.EnableProfiling, false),
-		DisableLeaderElection: lo.FromPtrOr(opts.DisableLeaderElection, false),
-		MemoryLimit:           lo.FromPtrOr(opts.MemoryLimit, -1),
-		LogLevel:              lo.FromPtrOr(opts.LogLevel, ""),
-		LogOutputPaths:        lo.FromPtrOr(opts.LogOutputPaths, "stdout"),
-		LogErrorOutputPaths:   lo.FromPtrOr(opts.LogErrorOutputPaths, "stderr"),
-		BatchMaxDuration:      lo.FromPtrOr(opts.BatchMaxDuration, 10*time.Second),
-		BatchIdleDuration:     lo.FromPtrOr(opts.BatchIdleDuration, time.Second),
+		ServiceName:                              lo.FromPtrOr(opts.ServiceName, ""),
+		MetricsPort:                              lo.FromPtrOr(opts.MetricsPort, 8080),
+		HealthProbePort:                          lo.FromPtrOr(opts.HealthProbePort, 8081),
+		KubeClientQPS:                            lo.FromPtrOr(opts.KubeClientQPS, 200),
+		KubeClientBurst:                          lo.FromPtrOr(opts.KubeClientBurst, 300),
+		EnableProfiling:                          lo.FromPtrOr(opts.EnableProfiling, false),
+		DisableLeaderElection:                    lo.FromPtrOr(opts.DisableLeaderElection, false),
+		MemoryLimit:                              lo.FromPtrOr(opts.MemoryLimit, -1),
+		LogLevel:                                 lo.FromPtrOr(opts.LogLevel, ""),
+		LogOutputPaths:                           lo.FromPtrOr(opts.LogOutputPaths, "stdout"),
+		LogErrorOutputPaths:                      lo.FromPtrOr(opts.LogErrorOutputPaths, "stderr"),
+		BatchMaxDuration:                         lo.FromPtrOr(opts.BatchMaxDuration, 10*time.Second),
+		BatchIdleDuration:                        lo.FromPtrOr(opts.BatchIdleDuration, time.Second),
+		GCWebhookURL:                             lo.FromPtrOr(opts.GCWebhookURL, ""),
+		GCWebhookStrict:                          lo.FromPtrOr(opts.GCWebhookStrict, false),
+		EnableAllocatableDiffAnnotation:          lo.FromPtrOr(opts.EnableAllocatableDiffAnnotation, false),
+		RegistrationRequiresStartupTaintsCleared: lo.FromPtrOr(opts.RegistrationRequiresStartupTaintsCleared, false),
+		DeferNodeClaimRehash:                     lo.FromPtrOr(opts.DeferNodeClaimRehash, false),
+		GCOnUnknownReady:                         lo.FromPtrOr(opts.GCOnUnknownReady, false),
+		GCUnknownReadyDuration:                   lo.FromPtrOr(opts.GCUnknownReadyDuration, 0),
 		FeatureGates: options.FeatureGates{
 			NodeRepair:              lo.FromPtrOr(opts.FeatureGates.NodeRepair, false),
 			ReservedCapacity:        lo.FromPtrOr(opts.FeatureGates.ReservedCapacity, false),
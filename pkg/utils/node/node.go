@@ -167,6 +167,21 @@ func GetCondition(n *corev1.Node, match corev1.NodeConditionType) corev1.NodeCon
 	return corev1.NodeCondition{}
 }
 
+// Reserved returns the resources the Node reports as reserved for system overhead, computed as the difference between
+// its capacity and its allocatable. Only resources reported in both capacity and allocatable are included.
+func Reserved(n *corev1.Node) corev1.ResourceList {
+	reserved := corev1.ResourceList{}
+	for name, capacity := range n.Status.Capacity {
+		allocatable, ok := n.Status.Allocatable[name]
+		if !ok {
+			continue
+		}
+		capacity.Sub(allocatable)
+		reserved[name] = capacity
+	}
+	return reserved
+}
+
 func IsManaged(node *corev1.Node, cp cloudprovider.CloudProvider) bool {
 	return lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
 		_, ok := node.Labels[v1.NodeClassLabelKey(object.GVK(nodeClass).GroupKind())]
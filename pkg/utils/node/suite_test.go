@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -83,4 +84,39 @@ var _ = Describe("NodeUtils", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(nodeClaims).To(HaveLen(0))
 	})
+	Context("Reserved", func() {
+		It("should return the difference between capacity and allocatable", func() {
+			testNode = test.Node(test.NodeOptions{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:              resource.MustParse("4"),
+					corev1.ResourceMemory:           resource.MustParse("16Gi"),
+					corev1.ResourceEphemeralStorage: resource.MustParse("100Gi"),
+				},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:              resource.MustParse("3920m"),
+					corev1.ResourceMemory:           resource.MustParse("15Gi"),
+					corev1.ResourceEphemeralStorage: resource.MustParse("90Gi"),
+				},
+			})
+			reserved := nodeutils.Reserved(testNode)
+			Expect(reserved).To(HaveLen(3))
+			Expect(reserved.Cpu().String()).To(Equal("80m"))
+			Expect(reserved.Memory().String()).To(Equal("1Gi"))
+			Expect(reserved.StorageEphemeral().String()).To(Equal("10Gi"))
+		})
+		It("should ignore resources that aren't reported as allocatable", func() {
+			testNode = test.Node(test.NodeOptions{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:  resource.MustParse("4"),
+					corev1.ResourcePods: resource.MustParse("110"),
+				},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("3920m"),
+				},
+			})
+			reserved := nodeutils.Reserved(testNode)
+			Expect(reserved).To(HaveLen(1))
+			Expect(reserved.Cpu().String()).To(Equal("80m"))
+		})
+	})
 })